@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type argumentType string
@@ -17,6 +18,7 @@ const (
 	boolean  argumentType = "bool"
 	str      argumentType = "str"
 	filename argumentType = "file"
+	duration argumentType = "duration"
 )
 
 type Argument struct {
@@ -26,10 +28,17 @@ type Argument struct {
 	ArgType      argumentType
 	IsVariadic   bool
 	IsRequired   bool
+	MinValues    int
+	MaxValues    int
 	ValidValues  []string
 	DefaultValue string
+	EnvVar       string
 	ValidatorFns [](func(string) error)
 	ValidatorRe  *regexp.Regexp
+	CompletionFn CompletionCallback
+	// Splits each value passed to the argument into several values, i.e. `a,b,c` with a delimiter of `,`
+	ValueDelimiter string
+	errs           []error
 }
 
 // A Builder method for creating a new argument. Valid values include <arg>, [arg] or simply the name of the arg
@@ -66,24 +75,19 @@ func NewArgument(name string) *Argument {
 
 // A method for setting the default value on an argument to be used when no value is provided but the argument value is required
 func (a *Argument) Default(val string) *Argument {
-	// Check if value valid
+	// Check if value valid. Errors are reported when the program is parsed
 	if len(a.ValidValues) > 0 {
 		if !a.testValue(val) {
-			// TODO: consider writing to stderr
-			fmt.Printf("error occurred when setting default value for argument: %v \n.  the passed value %v does not match the valid values: %v", a.Name, val, a.ValidValues)
-			if !isTestMode() {
-				os.Exit(1)
-			}
+			err := newDefinitionError(InvalidDefaultValue, "invalid default value for argument: `%v`, the passed value `%v` does not match the valid values: %v", a.Name, val, a.ValidValues)
+			a.errs = append(a.errs, err)
 		}
 	}
 	// verify value against validator fn if any
 	for _, fn := range a.ValidatorFns {
-		err := fn(val)
-		if err != nil {
-			fmt.Printf("you tried to set a default value for argument: %v, but the validator function returned an error for values: %v", a.Name, val)
-			if !isTestMode() {
-				os.Exit(1)
-			}
+		if err := fn(val); err != nil {
+			err := newDefinitionError(InvalidDefaultValue, "invalid default value for argument: `%v`, the validator function returned an error for value: `%v`", a.Name, val)
+			a.errs = append(a.errs, err)
+			break
 		}
 	}
 
@@ -91,6 +95,12 @@ func (a *Argument) Default(val string) *Argument {
 	return a
 }
 
+// Binds the argument to an environment variable. When no value is passed to the argument, its value is read from the environment variable if it is set, before falling back to the default value
+func (a *Argument) Env(val string) *Argument {
+	a.EnvVar = val
+	return a
+}
+
 // Simply sets the description or help string of the given argument
 func (a *Argument) Help(val string) *Argument {
 	a.HelpStr = val
@@ -103,6 +113,18 @@ func (a *Argument) Variadic(val bool) *Argument {
 	return a
 }
 
+// Sets the minimum number of values that must be passed to a variadic argument, i.e. "at least 2 files". The constraint is checked whenever values are passed to the argument, or always if the argument is required
+func (a *Argument) AtLeast(val int) *Argument {
+	a.MinValues = val
+	return a
+}
+
+// Sets the maximum number of values that can be passed to a variadic argument
+func (a *Argument) AtMost(val int) *Argument {
+	a.MaxValues = val
+	return a
+}
+
 // Sets whether an argument is required or not
 func (a *Argument) Required(val bool) *Argument {
 	a.IsRequired = val
@@ -127,11 +149,23 @@ func (a *Argument) ValidatorFunc(fn func(string) error) *Argument {
 	return a
 }
 
+// A method for setting a callback that provides completion candidates for the argument at runtime. It is used by the dynamic shell completion scripts
+func (a *Argument) CompletionFunc(fn CompletionCallback) *Argument {
+	a.CompletionFn = fn
+	return a
+}
+
 func (a *Argument) ValidatorRegex(val string) *Argument {
 	a.ValidatorRe = regexp.MustCompile(val)
 	return a
 }
 
+// Sets a delimiter used to split each value passed to the argument into several values, i.e. `--tags a,b,c`. Each of the values is validated separately
+func (a *Argument) Delimiter(val string) *Argument {
+	a.ValueDelimiter = val
+	return a
+}
+
 // A method for setting what the argument should be displayed as when printing help
 func (a *Argument) DisplayAs(val string) *Argument {
 	a.RawValue = val
@@ -141,49 +175,17 @@ func (a *Argument) DisplayAs(val string) *Argument {
 /****************************** Package utilities ********************************/
 
 func (a *Argument) addValidatorFns() {
-	switch a.ArgType {
-	case str:
-		{
-		}
-	case integer:
-		{
-			a.ValidatorFunc(func(s string) error {
-				_, err := strconv.Atoi(s)
-				if err != nil {
-					return fmt.Errorf("`%v` is not a valid integer", s)
-				}
-				return nil
-			})
-		}
-	case uinteger:
-		{
-			a.ValidatorFunc(func(s string) error {
-				_, err := strconv.ParseUint(s, 10, 64)
-				if err != nil {
-					return fmt.Errorf("`%v` is not a positive integer", s)
-				}
+	argType := a.ArgType
 
-				return nil
-			})
-		}
-	case float:
+	switch argType {
+	case str:
 		{
-			a.ValidatorFunc(func(s string) error {
-				_, err := strconv.ParseFloat(s, 64)
-				if err != nil {
-					return fmt.Errorf("`%v` is not a valid float", s)
-				}
-				return nil
-			})
-
 		}
-	case boolean:
+	case integer, uinteger, float, boolean, duration:
 		{
 			a.ValidatorFunc(func(s string) error {
-				if s != "true" && s != "false" {
-					return fmt.Errorf("`%v` is not a valid boolean", s)
-				}
-				return nil
+				_, err := convertValue(argType, s)
+				return err
 			})
 		}
 	case filename:
@@ -198,12 +200,47 @@ func (a *Argument) addValidatorFns() {
 
 	default:
 		{
-			fmt.Println(fmt.Errorf("found unknown argument type: `%v` for argument: `%v`", a.ArgType, a.getRawValue()))
-			if !isTestMode() {
-				os.Exit(1)
-			}
+			err := newDefinitionError(InvalidArgumentType, "found unknown argument type: `%v` for argument: `%v`", a.ArgType, a.getRawValue())
+			a.errs = append(a.errs, err)
+		}
+	}
+}
+
+// Converts a raw value into the go type corresponding to the argument type. The same conversion is used by the type validators and the typed value getters
+func convertValue(argType argumentType, s string) (interface{}, error) {
+	switch argType {
+	case integer:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not a valid integer", s)
 		}
+		return v, nil
+	case uinteger:
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not a positive integer", s)
+		}
+		return uint(v), nil
+	case float:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not a valid float", s)
+		}
+		return v, nil
+	case boolean:
+		if s != "true" && s != "false" {
+			return nil, fmt.Errorf("`%v` is not a valid boolean", s)
+		}
+		return s == "true", nil
+	case duration:
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not a valid duration", s)
+		}
+		return v, nil
 	}
+
+	return s, nil
 }
 
 func (a *Argument) testValue(val string) bool {
@@ -239,6 +276,13 @@ func (a *Argument) hasDefaultValue() bool {
 	return len(a.DefaultValue) > 0
 }
 
+func (a *Argument) lookupEnv() (string, bool) {
+	if len(a.EnvVar) == 0 {
+		return "", false
+	}
+	return os.LookupEnv(a.EnvVar)
+}
+
 func newArgument(val string, help string) *Argument {
 	arg := NewArgument(val)
 	arg.Help(help)
@@ -270,15 +314,27 @@ func (a *Argument) getRawValue() string {
 
 /****************************** Interface implementations ********************************/
 
+// Returns the value of the argument as displayed in help output. Arguments with a delimiter are displayed as `<tag>[,<tag>...]`
+func (a *Argument) displayValue() string {
+	raw := a.getRawValue()
+	if len(a.ValueDelimiter) > 0 && len(a.RawValue) == 0 {
+		return fmt.Sprintf("%v[%v%v...]", raw, a.ValueDelimiter, raw)
+	}
+	return raw
+}
+
 func (a *Argument) generate(app *Command) (string, string) {
 	var leading strings.Builder
 	var floating strings.Builder
 
-	leading.WriteString(a.getRawValue())
+	leading.WriteString(a.displayValue())
 	floating.WriteString(a.HelpStr)
 	if a.hasDefaultValue() {
 		floating.WriteString(fmt.Sprintf(" (default: %v)", a.DefaultValue))
 	}
+	if len(a.EnvVar) > 0 {
+		floating.WriteString(fmt.Sprintf(" [env: %v]", a.EnvVar))
+	}
 
 	return leading.String(), floating.String()
 }
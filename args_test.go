@@ -67,11 +67,10 @@ func TestArgValidValues(t *testing.T) {
 	assert(t, !arg.testValue("else"), "Values validation not working properly")
 	assertEq(t, arg.getRawValue(), "language", "Failed to set raw arg value using DisplayAs method")
 
-	exec := func() {
-		arg.Default("NEW")
-	}
-	expected := fmt.Sprintf("error occurred when setting default value for argument: %v \n.  the passed value %v does not match the valid values: %v", arg.Name, "NEW", arg.ValidValues)
-	assertStdOut(t, expected, exec, "Argument validation for arguments with valid values is buggy")
+	arg.Default("NEW")
+	expected := fmt.Sprintf("invalid default value for argument: `%v`, the passed value `%v` does not match the valid values: %v", arg.Name, "NEW", arg.ValidValues)
+	assertEq(t, len(arg.errs), 1, "Argument validation for arguments with valid values is buggy")
+	assertEq(t, arg.errs[0].Error(), expected, "Argument validation for arguments with valid values is buggy")
 }
 
 func TestArgValidatorFunc(t *testing.T) {
@@ -88,15 +87,13 @@ func TestArgValidatorFunc(t *testing.T) {
 	assertEq(t, arg.getRawValue(), "int", "Failed to set raw arg value using DisplayAs method")
 	assert(t, arg.testValue("2"), "Strconv validator function working incorrectly")
 
-	exec := func() {
-		arg.Default("notInt")
-	}
-	expected := fmt.Sprintf("you tried to set a default value for argument: %v, but the validator function returned an error for values: %v", arg.Name, "notInt")
-	assertStdOut(t, expected, exec, "Argument validation for arguments with validator functions is buggy")
+	arg.Default("notInt")
+	expected := fmt.Sprintf("invalid default value for argument: `%v`, the validator function returned an error for value: `%v`", arg.Name, "notInt")
+	assertEq(t, len(arg.errs), 1, "Argument validation for arguments with validator functions is buggy")
+	assertEq(t, arg.errs[0].Error(), expected, "Argument validation for arguments with validator functions is buggy")
 }
 
 func TestArgRegexValidator(t *testing.T) {
-	clearCache()
 	{
 		arg := NewArgument("version").ValidatorRegex(`^v[\d\.]`)
 		assert(t, arg.testValue("v0.1.0"), "Regex validation is buggy")
@@ -135,12 +132,11 @@ func TestArgTypeValidation(t *testing.T) {
 		assert(t, arg.testValue("go.mod"), "Filename arg validation faulty")
 	}
 	{
-		exec := func() {
-			NewArgument("<fake:arg>")
-		}
-		expected := "found unknown argument type: `fake` for argument: `<arg>`\n"
+		arg := NewArgument("<fake:arg>")
+		expected := "found unknown argument type: `fake` for argument: `<arg>`"
 
-		assertStdOut(t, expected, exec, "Unknown arg types pass on undetected")
+		assertEq(t, len(arg.errs), 1, "Unknown arg types pass on undetected")
+		assertEq(t, arg.errs[0].Error(), expected, "Unknown arg types pass on undetected")
 	}
 }
 
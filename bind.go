@@ -0,0 +1,289 @@
+package gommander
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type bindingKind byte
+
+const (
+	flagBinding bindingKind = iota
+	optionBinding
+	argumentBinding
+)
+
+// A struct field whose value is filled from the parser matches once parsing succeeds
+type fieldBinding struct {
+	kind  bindingKind
+	name  string
+	field reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Registers flags, options, arguments and subcommands on the command from the tags of a struct, then fills the struct with the parsed values before the command's callback is invoked.
+// The target must be a pointer to a struct. The `gommander` tag holds the definition of the field and is interpreted in the same way as the `.Flag()`, `.Option()` and `.Argument()` methods:
+//
+//	type Config struct {
+//		Verbose bool          `gommander:"-v --verbose" help:"Print more output"`
+//		Port    int           `gommander:"-p --port <port>" help:"The port to use" default:"8080" env:"PORT"`
+//		Hosts   []string      `gommander:"<hosts>" help:"The hosts to connect to"`
+//		Deploy  DeployConfig  `gommander:"deploy" help:"Deploy the app"`
+//	}
+//
+// Flags are bound to bool fields, or to int fields which receive the number of times the flag was passed. Untyped arguments take the type of the field, i.e. `<port>` on an int field is validated as `<int:port>`. Slice fields collect every instance of an option or the values of a variadic argument.
+// Nested struct fields are bound to subcommands with the name in the tag. Other supported tags are `default`, `env` and `required`
+func (c *Command) Bind(target interface{}) *Command {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		err := newDefinitionError(InvalidBinding, "cannot bind to a value of type: `%T`, expected a pointer to a struct", target)
+		c.definitionErrs = append(c.definitionErrs, err)
+		return c
+	}
+
+	c.bindStruct(ptr.Elem())
+	return c
+}
+
+func (c *Command) bindStruct(val reflect.Value) {
+	for i := 0; i < val.NumField(); i++ {
+		sf := val.Type().Field(i)
+		spec, tagged := sf.Tag.Lookup("gommander")
+		if !tagged || !sf.IsExported() {
+			continue
+		}
+
+		field := val.Field(i)
+		help := sf.Tag.Get("help")
+
+		if field.Kind() == reflect.Struct {
+			subCmd, err := c.findSubcommand(spec)
+			if err != nil {
+				subCmd = c.SubCommand(spec)
+			}
+			if len(help) > 0 {
+				subCmd.Help(help)
+			}
+			subCmd.bindStruct(field)
+			continue
+		}
+
+		if !isBindable(field.Type()) {
+			err := newDefinitionError(InvalidBinding, "cannot bind field: `%v` of unsupported type: `%v`", sf.Name, field.Type())
+			c.definitionErrs = append(c.definitionErrs, err)
+			continue
+		}
+
+		if err := c.bindField(sf, field, spec, help); err != nil {
+			c.definitionErrs = append(c.definitionErrs, DefinitionError{Kind: InvalidBinding, Message: err.Error()})
+		}
+	}
+}
+
+func (c *Command) bindField(sf reflect.StructField, field reflect.Value, spec, help string) error {
+	var arg *Argument
+	binding := fieldBinding{field: field}
+
+	switch {
+	case strings.HasPrefix(spec, "<") || strings.HasPrefix(spec, "["):
+		arg = newArgument(spec, help)
+		if field.Kind() == reflect.Slice {
+			arg.Variadic(true)
+		}
+		c.AddArgument(arg)
+		binding.kind, binding.name = argumentBinding, arg.Name
+
+	case strings.ContainsAny(spec, "<["):
+		opt := newOption(spec, help, sf.Tag.Get("required") == "true")
+		if env, exists := sf.Tag.Lookup("env"); exists {
+			opt.Env(env)
+		}
+		arg = opt.Arg
+		c.AddOption(&opt)
+		binding.kind, binding.name = optionBinding, opt.Name
+		if len(opt.Name) == 0 {
+			binding.name = opt.ShortVal
+		}
+
+	default:
+		if field.Kind() != reflect.Bool && field.Kind() != reflect.Int {
+			return fmt.Errorf("cannot bind flag: `%v` to field: `%v` of type: `%v`, flags can only be bound to bool fields, or int fields for their count", spec, sf.Name, field.Type())
+		}
+		flag := newFlag(spec, help)
+		c.AddFlag(&flag)
+		binding.kind, binding.name = flagBinding, flag.Name
+		if len(flag.Name) == 0 {
+			binding.name = flag.ShortVal
+		}
+	}
+
+	if arg != nil {
+		// untyped arguments are validated using the type of the field
+		if arg.ArgType == str && !strings.ContainsRune(spec, ':') {
+			if argType := inferArgType(field.Type()); argType != str {
+				arg.Type(argType)
+			}
+		}
+		if binding.kind == argumentBinding {
+			if env, exists := sf.Tag.Lookup("env"); exists {
+				arg.Env(env)
+			}
+		}
+		if def, exists := sf.Tag.Lookup("default"); exists {
+			arg.Default(def)
+			if err := setFieldValue(field, []string{def}); err != nil {
+				return fmt.Errorf("invalid default value for field: `%v`: %v", sf.Name, err)
+			}
+		}
+	}
+
+	c.bindings = append(c.bindings, binding)
+	return nil
+}
+
+// Fills the structs bound to the command and its ancestors with the values from the parser matches
+func (c *Command) fillBindings(pm *ParserMatches) *Error {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		// the bindings of each command are read from its own matches
+		level := pm.ForCommand(cmd)
+		if level == nil {
+			continue
+		}
+
+		for _, b := range cmd.bindings {
+			var values []string
+
+			switch b.kind {
+			case flagBinding:
+				// fields of flags that were not passed keep their value, so negatable flags can default to true
+				if state := level.GetFlagState(b.name); state != FlagNotGiven {
+					if b.field.Kind() == reflect.Int {
+						b.field.SetInt(int64(level.GetFlagCount(b.name)))
+					} else {
+						b.field.SetBool(state == FlagTrue)
+					}
+				}
+				continue
+			case optionBinding:
+				if level.ContainsOption(b.name) {
+					values = level.GetAllOptionInstances(b.name)
+				}
+			case argumentBinding:
+				for _, v := range level.argMatches {
+					if v.instanceOf.Name == b.name {
+						values = v.values
+					}
+				}
+			}
+
+			if len(values) == 0 {
+				continue
+			}
+			if err := setFieldValue(b.field, values); err != nil {
+				err := generateError(cmd, InvalidArgumentValue, []string{strings.Join(values, " "), err.Error()})
+				return &err
+			}
+		}
+	}
+
+	return nil
+}
+
+/****************************** Binding utilities ****************************/
+
+func isBindable(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func inferArgType(t reflect.Type) argumentType {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return duration
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolean
+	case reflect.Float32, reflect.Float64:
+		return float
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return integer
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uinteger
+	}
+	return str
+}
+
+func setFieldValue(field reflect.Value, values []string) error {
+	if field.Kind() != reflect.Slice {
+		return setScalarValue(field, values[0])
+	}
+
+	slice := reflect.MakeSlice(field.Type(), 0, len(values))
+	for _, v := range values {
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := setScalarValue(elem, v); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	field.Set(slice)
+	return nil
+}
+
+func setScalarValue(field reflect.Value, s string) error {
+	if field.Type() == durationType {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("`%v` is not a valid duration", s)
+		}
+		field.SetInt(int64(v))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("`%v` is not a valid boolean", s)
+		}
+		field.SetBool(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("`%v` is not a valid float", s)
+		}
+		field.SetFloat(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("`%v` is not a valid %v", s, field.Type())
+		}
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("`%v` is not a valid %v", s, field.Type())
+		}
+		field.SetUint(v)
+	}
+	return nil
+}
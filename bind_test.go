@@ -0,0 +1,100 @@
+package gommander
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type deployConfig struct {
+	Region  string        `gommander:"-r --region <region>" help:"The region to deploy to" default:"eu-west-1"`
+	Timeout time.Duration `gommander:"--timeout <timeout>" help:"The deploy timeout" default:"1m"`
+	Force   bool          `gommander:"-f --force" help:"Force the deployment"`
+	Targets []string      `gommander:"<targets>" help:"The targets to deploy"`
+}
+
+type appConfig struct {
+	Verbose bool         `gommander:"-V --verbose" help:"Print more output"`
+	Quiet   int          `gommander:"-q --quiet" help:"Print less output"`
+	Port    int          `gommander:"-p --port <port>" help:"The port to use" default:"8080" env:"GOMMANDER_TEST_PORT"`
+	Tags    []string     `gommander:"-t --tag <tag>" help:"Tags to apply"`
+	Ratio   float64      `gommander:"[float:ratio]" help:"Some ratio"`
+	Deploy  deployConfig `gommander:"deploy" help:"Deploy the app"`
+	ignored string
+}
+
+func TestBindRegistration(t *testing.T) {
+	cfg := appConfig{}
+	app := App().Bind(&cfg)
+
+	assertEq(t, len(app.getDefinitionErrors()), 0, "Valid struct reported definition errors")
+	assertEq(t, len(app.options), 2, "Options not registered from struct tags")
+	assertEq(t, len(app.arguments), 1, "Arguments not registered from struct tags")
+	assertEq(t, app.options[0].Arg.ArgType, integer, "Argument type not inferred from the field type")
+	assertEq(t, app.options[0].EnvVar, "GOMMANDER_TEST_PORT", "Env var not set from struct tag")
+	assertEq(t, cfg.Port, 8080, "Default value not assigned to the field")
+
+	deploy, err := app.findSubcommand("deploy")
+	assert(t, err == nil, "Nested struct not registered as a subcommand")
+	assertEq(t, deploy.help, "Deploy the app", "Subcommand help not set from struct tag")
+	assert(t, deploy.arguments[0].IsVariadic, "Slice argument not marked as variadic")
+	assertEq(t, deploy.options[1].Arg.ArgType, duration, "Duration type not inferred from the field type")
+}
+
+func TestBindValues(t *testing.T) {
+	{
+		cfg := appConfig{}
+		app := App().Set(OverrideAllDefaultListeners, true).Bind(&cfg)
+		app.Action(func(pm *ParserMatches) {
+			assertEq(t, cfg.Port, 3000, "Struct not filled before the callback is invoked")
+		})
+
+		err := app.ExecuteFrom([]string{"bin", "0.5", "-V", "-qq", "-p", "3000", "-t", "a", "-t", "b"})
+		assert(t, err == nil, "Unexpected error when parsing bound struct")
+		assert(t, cfg.Verbose, "Flag value not bound")
+		assertEq(t, cfg.Quiet, 2, "Flag count not bound to int field")
+		assertEq(t, cfg.Ratio, 0.5, "Argument value not bound")
+		assertDeepEq(t, cfg.Tags, []string{"a", "b"}, "Option instances not bound")
+	}
+
+	{
+		cfg := appConfig{}
+		app := App().Set(OverrideAllDefaultListeners, true).Bind(&cfg)
+		app.Action(func(pm *ParserMatches) {})
+
+		err := app.ExecuteFrom([]string{"bin", "-V", "-p", "9000", "deploy", "web", "api", "--timeout", "30s", "-f"})
+		assert(t, err == nil, "Unexpected error when parsing bound subcommand")
+		assert(t, cfg.Verbose, "Flag of the parent command not bound")
+		assertEq(t, cfg.Port, 9000, "Option of the parent command not bound")
+		assertDeepEq(t, cfg.Deploy.Targets, []string{"web", "api"}, "Variadic argument not bound")
+		assertEq(t, cfg.Deploy.Timeout, 30*time.Second, "Duration value not bound")
+		assertEq(t, cfg.Deploy.Region, "eu-west-1", "Default value overwritten")
+		assert(t, cfg.Deploy.Force, "Subcommand flag not bound")
+	}
+
+	{
+		cfg := appConfig{}
+		app := App().Set(OverrideAllDefaultListeners, true).Bind(&cfg)
+
+		err := app.ExecuteFrom([]string{"bin", "-p", "eighty"})
+		e, ok := err.(*Error)
+		assert(t, ok && e.GetKind() == InvalidArgumentValue, "Invalid value for inferred type not reported")
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	app := App()
+	app.Bind(appConfig{})
+	app.SubCommand("flags").Bind(&struct {
+		Verbose string `gommander:"-v --verbose"`
+	}{})
+	app.SubCommand("types").Bind(&struct {
+		Values map[string]string `gommander:"--values <values>"`
+	}{})
+
+	errs := app.getDefinitionErrors()
+	assertEq(t, len(errs), 3, "Invalid bindings not reported")
+	assert(t, strings.Contains(errs[0].Error(), "expected a pointer to a struct"), "Wrong error for non-pointer target")
+	assert(t, strings.Contains(errs[1].Error(), "flags can only be bound to bool fields, or int fields"), "Wrong error for non-bool flag")
+	assert(t, strings.Contains(errs[2].Error(), "unsupported type"), "Wrong error for unsupported field type")
+}
@@ -0,0 +1,384 @@
+package gommander
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A single difference between two specs of a command tree
+type SpecChange struct {
+	// The path of the command in which the change was found, i.e. `git remote add`
+	Command string
+	// A description of the change
+	Message string
+}
+
+// The result of comparing two specs of a command tree. Breaking changes are those that can cause scripts written against the old command tree to fail, while additions extend the command tree without affecting existing usage
+type CompatReport struct {
+	Breaking  []SpecChange
+	Additions []SpecChange
+}
+
+// Returns true if no breaking changes were found
+func (r *CompatReport) IsCompatible() bool {
+	return len(r.Breaking) == 0
+}
+
+// Returns a human-readable summary of the changes, with breaking changes listed first
+func (r *CompatReport) String() string {
+	var report strings.Builder
+
+	write := func(heading string, changes []SpecChange) {
+		report.WriteString(fmt.Sprintf("%v (%v):\n", heading, len(changes)))
+		for _, c := range changes {
+			report.WriteString(fmt.Sprintf("    %v: %v\n", c.Command, c.Message))
+		}
+	}
+
+	write("BREAKING CHANGES", r.Breaking)
+	write("ADDITIONS", r.Additions)
+
+	return report.String()
+}
+
+// Compares the spec of a new version of a command tree against the spec of an older version and reports the breaking changes and additions between them.
+// Breaking changes include removed subcommands, aliases, flags and options, renamed flags and options, changed short values, options that start or stop taking a value, arguments and options that became required, narrowed valid values and changed argument types
+func CompareSpecs(old, new Spec) CompatReport {
+	report := CompatReport{}
+	report.compareCommands(old.Program.Name, old.Program, new.Program)
+	return report
+}
+
+func (r *CompatReport) breaking(cmd string, format string, args ...interface{}) {
+	r.Breaking = append(r.Breaking, SpecChange{Command: cmd, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *CompatReport) addition(cmd string, format string, args ...interface{}) {
+	r.Additions = append(r.Additions, SpecChange{Command: cmd, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *CompatReport) compareCommands(path string, old, new CommandSpec) {
+	// aliases
+	for _, a := range old.Aliases {
+		if !containsString(new.Aliases, a) && a != new.Name {
+			r.breaking(path, "alias `%v` was removed", a)
+		}
+	}
+	for _, a := range new.Aliases {
+		if !containsString(old.Aliases, a) && a != old.Name {
+			r.addition(path, "alias `%v` was added", a)
+		}
+	}
+
+	// positional arguments are matched by their position
+	for i, o := range old.Arguments {
+		if i >= len(new.Arguments) {
+			r.breaking(path, "argument `%v` was removed", o.Name)
+			continue
+		}
+		r.compareArguments(path, fmt.Sprintf("argument `%v`", o.Name), o, new.Arguments[i])
+	}
+	for i := len(old.Arguments); i < len(new.Arguments); i++ {
+		if a := new.Arguments[i]; a.Required && len(a.Default) == 0 {
+			r.breaking(path, "required argument `%v` was added", a.Name)
+		} else {
+			r.addition(path, "argument `%v` was added", a.Name)
+		}
+	}
+
+	// flags
+	for _, o := range old.Flags {
+		n, exists := findFlagSpec(new.Flags, o)
+		if !exists {
+			r.breaking(path, "flag `%v` was removed", switchName(o.Short, o.Long))
+			continue
+		}
+		if len(o.Short) > 0 && o.Short != n.Short {
+			r.breaking(path, "short value of flag `%v` changed from `%v` to `%v`", switchName(o.Short, o.Long), o.Short, n.Short)
+		}
+		if len(o.Long) > 0 && o.Long != n.Long {
+			r.breaking(path, "flag `%v` was renamed to `%v`", o.Long, n.Long)
+		}
+		if o.Global && !n.Global {
+			r.breaking(path, "flag `%v` is no longer global", switchName(o.Short, o.Long))
+		} else if !o.Global && n.Global {
+			r.addition(path, "flag `%v` is now global", switchName(n.Short, n.Long))
+		}
+		if o.Negatable && !n.Negatable {
+			r.breaking(path, "flag `%v` is no longer negatable", switchName(o.Short, o.Long))
+		} else if !o.Negatable && n.Negatable {
+			r.addition(path, "flag `%v` is now negatable", switchName(n.Short, n.Long))
+		}
+	}
+	for _, n := range new.Flags {
+		if _, exists := findFlagSpec(old.Flags, n); !exists {
+			r.addition(path, "flag `%v` was added", switchName(n.Short, n.Long))
+		}
+	}
+
+	// options
+	for _, o := range old.Options {
+		name := switchName(o.Short, o.Long)
+		n, exists := findOptionSpec(new.Options, o)
+		if !exists {
+			r.breaking(path, "option `%v` was removed", name)
+			continue
+		}
+		if len(o.Short) > 0 && o.Short != n.Short {
+			r.breaking(path, "short value of option `%v` changed from `%v` to `%v`", name, o.Short, n.Short)
+		}
+		if len(o.Long) > 0 && o.Long != n.Long {
+			r.breaking(path, "option `%v` was renamed to `%v`", o.Long, n.Long)
+		}
+		if !o.Required && n.Required && !optionHasDefault(n) {
+			r.breaking(path, "option `%v` is now required", name)
+		}
+		if len(o.Arguments) > 0 && len(n.Arguments) > 0 && len(o.Arguments) != len(n.Arguments) {
+			r.breaking(path, "option `%v` now expects %v value(s) per occurrence", name, len(n.Arguments))
+		}
+		if o.Global && !n.Global {
+			r.breaking(path, "option `%v` is no longer global", name)
+		} else if !o.Global && n.Global {
+			r.addition(path, "option `%v` is now global", name)
+		}
+		if o.OptionalValue && !n.OptionalValue {
+			r.breaking(path, "the value of option `%v` is no longer optional", name)
+		} else if !o.OptionalValue && n.OptionalValue {
+			r.breaking(path, "the value of option `%v` is now optional and must be attached to the option", name)
+		}
+		if o.Map != n.Map {
+			r.breaking(path, "option `%v` changed whether it expects `key=value` pairs", name)
+		}
+		if len(n.ValidKeys) > 0 && len(o.ValidKeys) == 0 {
+			r.breaking(path, "option `%v` is now restricted to the keys: `[%v]`", name, strings.Join(n.ValidKeys, ", "))
+		} else if len(n.ValidKeys) > 0 {
+			for _, k := range o.ValidKeys {
+				if !containsString(n.ValidKeys, k) {
+					r.breaking(path, "key `%v` is no longer accepted by option `%v`", k, name)
+				}
+			}
+		}
+		if len(o.Arguments) == 0 && len(n.Arguments) > 0 {
+			r.breaking(path, "option `%v` now expects a value", name)
+		} else if len(o.Arguments) > 0 && len(n.Arguments) == 0 {
+			r.breaking(path, "option `%v` no longer expects a value", name)
+		}
+		// the arguments of an option are matched by their position
+		for i := 0; i < len(o.Arguments) && i < len(n.Arguments); i++ {
+			desc := fmt.Sprintf("the argument of option `%v`", name)
+			if len(o.Arguments) > 1 {
+				desc = fmt.Sprintf("argument `%v` of option `%v`", o.Arguments[i].Name, name)
+			}
+			r.compareArguments(path, desc, o.Arguments[i], n.Arguments[i])
+		}
+	}
+	for _, n := range new.Options {
+		if _, exists := findOptionSpec(old.Options, n); exists {
+			continue
+		}
+		if n.Required && !optionHasDefault(n) {
+			r.breaking(path, "required option `%v` was added", switchName(n.Short, n.Long))
+		} else {
+			r.addition(path, "option `%v` was added", switchName(n.Short, n.Long))
+		}
+	}
+
+	// option groups restrict how flags and options can be combined, so removing one is an addition while adding one is breaking
+	for _, o := range old.OptionGroups {
+		if !containsOptionGroupSpec(new.OptionGroups, o) {
+			r.addition(path, "%v was removed", o.describe())
+		}
+	}
+	for _, n := range new.OptionGroups {
+		if !containsOptionGroupSpec(old.OptionGroups, n) {
+			r.breaking(path, "%v was added", n.describe())
+		}
+	}
+
+	// subcommands are matched by their names, or by their aliases when renamed
+	for _, o := range old.SubCommands {
+		n, exists := findCommandSpec(new.SubCommands, o.Name)
+		if !exists {
+			r.breaking(path, "subcommand `%v` was removed", o.Name)
+			continue
+		}
+		if n.Name != o.Name {
+			r.addition(path, "subcommand `%v` was renamed to `%v`, the old name is kept as an alias", o.Name, n.Name)
+		}
+		r.compareCommands(path+" "+n.Name, o, n)
+	}
+	for _, n := range new.SubCommands {
+		if _, exists := findCommandSpec(old.SubCommands, n.Name); !exists && !anyCommandAlias(old.SubCommands, n.Aliases) {
+			r.addition(path, "subcommand `%v` was added", n.Name)
+		}
+	}
+}
+
+func (r *CompatReport) compareArguments(path, desc string, old, new ArgumentSpec) {
+	// any value is accepted by string arguments, so changing the type to a string is not breaking
+	oldType, newType := specArgType(old), specArgType(new)
+	if oldType != newType && newType != str {
+		r.breaking(path, "type of %v changed from `%v` to `%v`", desc, oldType, newType)
+	}
+	if !old.Required && new.Required && len(new.Default) == 0 {
+		r.breaking(path, "%v is now required", desc)
+	}
+	if old.Variadic && !new.Variadic {
+		r.breaking(path, "%v is no longer variadic", desc)
+	} else if !old.Variadic && new.Variadic {
+		r.addition(path, "%v is now variadic", desc)
+	}
+	if new.MinValues > old.MinValues {
+		r.breaking(path, "%v now expects at least %v values", desc, new.MinValues)
+	}
+	if new.MaxValues > 0 && (old.MaxValues == 0 || new.MaxValues < old.MaxValues) {
+		r.breaking(path, "%v now accepts at most %v values", desc, new.MaxValues)
+	}
+
+	// valid values
+	if len(new.ValidValues) > 0 {
+		removed := []string{}
+		for _, v := range old.ValidValues {
+			if !containsString(new.ValidValues, v) {
+				removed = append(removed, v)
+			}
+		}
+		if len(old.ValidValues) == 0 {
+			r.breaking(path, "%v is now restricted to the values: `[%v]`", desc, strings.Join(new.ValidValues, ", "))
+		} else if len(removed) > 0 {
+			r.breaking(path, "valid values `[%v]` were removed from %v", strings.Join(removed, ", "), desc)
+		}
+	}
+	if len(old.ValidValues) > 0 {
+		added := []string{}
+		for _, v := range new.ValidValues {
+			if !containsString(old.ValidValues, v) {
+				added = append(added, v)
+			}
+		}
+		if len(new.ValidValues) == 0 {
+			r.addition(path, "%v is no longer restricted to a set of valid values", desc)
+		} else if len(added) > 0 {
+			r.addition(path, "valid values `[%v]` were added to %v", strings.Join(added, ", "), desc)
+		}
+	}
+}
+
+/****************************** Spec lookups ****************************/
+
+// Returns the index of the switch matching the target, compared by long value first, then by name and then by short value, so that a switch whose long value changed is found as renamed rather than removed. It returns -1 if none matches
+func matchSwitchSpec(target [3]string, switches [][3]string) int {
+	for k := range target {
+		if len(target[k]) == 0 {
+			continue
+		}
+		for i, s := range switches {
+			if s[k] == target[k] {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func findFlagSpec(flags []FlagSpec, target FlagSpec) (FlagSpec, bool) {
+	switches := [][3]string{}
+	for _, f := range flags {
+		switches = append(switches, [3]string{f.Long, f.Name, f.Short})
+	}
+	if i := matchSwitchSpec([3]string{target.Long, target.Name, target.Short}, switches); i >= 0 {
+		return flags[i], true
+	}
+	return FlagSpec{}, false
+}
+
+func findOptionSpec(opts []OptionSpec, target OptionSpec) (OptionSpec, bool) {
+	switches := [][3]string{}
+	for _, o := range opts {
+		switches = append(switches, [3]string{o.Long, o.Name, o.Short})
+	}
+	if i := matchSwitchSpec([3]string{target.Long, target.Name, target.Short}, switches); i >= 0 {
+		return opts[i], true
+	}
+	return OptionSpec{}, false
+}
+
+// Groups are matched by their kind, condition and members, regardless of the order of the members
+func containsOptionGroupSpec(groups []OptionGroupSpec, target OptionGroupSpec) bool {
+	for _, g := range groups {
+		if g.Kind != target.Kind || g.Option != target.Option || g.Value != target.Value || len(g.Members) != len(target.Members) {
+			continue
+		}
+		matched := true
+		for _, m := range target.Members {
+			if !containsString(g.Members, m) {
+				matched = false
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (g OptionGroupSpec) describe() string {
+	desc := fmt.Sprintf("`%v` option group `[%v]`", g.Kind, strings.Join(g.Members, ", "))
+	if len(g.Option) > 0 && len(g.Value) > 0 {
+		desc += fmt.Sprintf(" on `%v=%v`", g.Option, g.Value)
+	} else if len(g.Option) > 0 {
+		desc += fmt.Sprintf(" on `%v`", g.Option)
+	}
+	return desc
+}
+
+func specArgType(arg ArgumentSpec) argumentType {
+	if len(arg.Type) == 0 {
+		return str
+	}
+	return argumentType(arg.Type)
+}
+
+func optionHasDefault(opt OptionSpec) bool {
+	return len(opt.Arguments) > 0 && len(opt.Arguments[0].Default) > 0
+}
+
+func findCommandSpec(cmds []CommandSpec, name string) (CommandSpec, bool) {
+	for _, c := range cmds {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	// the command may have been renamed while keeping the old name as an alias
+	for _, c := range cmds {
+		if containsString(c.Aliases, name) {
+			return c, true
+		}
+	}
+	return CommandSpec{}, false
+}
+
+func anyCommandAlias(cmds []CommandSpec, aliases []string) bool {
+	for _, a := range aliases {
+		if _, exists := findCommandSpec(cmds, a); exists {
+			return true
+		}
+	}
+	return false
+}
+
+func switchName(short, long string) string {
+	if len(long) > 0 {
+		return long
+	}
+	return short
+}
+
+func containsString(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package gommander
+
+import (
+	"strings"
+	"testing"
+)
+
+func _changeMessages(changes []SpecChange) []string {
+	messages := []string{}
+	for _, c := range changes {
+		messages = append(messages, c.Command+": "+c.Message)
+	}
+	return messages
+}
+
+func TestCompareSpecs(t *testing.T) {
+	previous := App().Name("git")
+	previous.AddFlag(NewFlag("verbose").Short('V').Help("Print more output").Global(true))
+
+	previousRemote := previous.SubCommand("remote").Alias("r").Alias("rem")
+	previousRemote.SubCommand("add").
+		Argument("<name>", "The name of the remote").
+		Argument("[url]", "The url of the remote").
+		Flag("-f --fetch", "Fetch the remote").
+		Option("-t --track <branch>", "Track a branch").
+		AddOption(NewOption("mirror").AddArgument(NewArgument("<mode>").ValidateWith([]string{"fetch", "push"})))
+	previous.SubCommand("commit").Option("-m --message <msg>", "The commit message")
+	previous.SubCommand("archive").Option("-l --level <int:level>", "The compression level")
+	previous.SubCommand("status")
+
+	old := previous.ExportSpec()
+	identical := CompareSpecs(old, previous.ExportSpec())
+	assert(t, identical.IsCompatible(), "Identical specs reported as incompatible")
+	assertEq(t, len(identical.Additions), 0, "Additions reported for identical specs")
+
+	app := App().Name("git")
+	app.AddFlag(NewFlag("verbose").Short('V').Help("Print more output"))
+
+	remote := app.SubCommand("remote").Alias("r")
+	remote.SubCommand("add").
+		Argument("<name>", "The name of the remote").
+		Argument("<int:url>", "The url of the remote").
+		Argument("[extra]", "Extra args").
+		Flag("-F --fetch", "Fetch the remote").
+		Flag("--tags", "Import tags").
+		AddOption(NewOption("mirror").AddArgument(NewArgument("<mode>").ValidateWith([]string{"fetch", "all"}))).
+		AddOption(NewOption("name").Required(true).AddArgument(NewArgument("<name>")))
+	app.SubCommand("save").Alias("commit").AddOption(NewOption("message").Short('m').Help("Prompt for the commit message"))
+	app.SubCommand("archive").Option("-l --compression <int:level>", "The compression level")
+	app.SubCommand("log")
+
+	report := CompareSpecs(old, app.ExportSpec())
+
+	assertDeepEq(t, _changeMessages(report.Breaking), []string{
+		"git: flag `--verbose` is no longer global",
+		"git remote: alias `rem` was removed",
+		"git remote add: type of argument `url` changed from `str` to `int`",
+		"git remote add: argument `url` is now required",
+		"git remote add: short value of flag `--fetch` changed from `-f` to `-F`",
+		"git remote add: option `--track` was removed",
+		"git remote add: valid values `[push]` were removed from the argument of option `--mirror`",
+		"git remote add: required option `--name` was added",
+		"git save: option `--message` no longer expects a value",
+		"git archive: option `--level` was renamed to `--compression`",
+		"git: subcommand `status` was removed",
+	}, "Wrong breaking changes reported")
+
+	assertDeepEq(t, _changeMessages(report.Additions), []string{
+		"git remote add: argument `extra` was added",
+		"git remote add: flag `--tags` was added",
+		"git remote add: valid values `[all]` were added to the argument of option `--mirror`",
+		"git: subcommand `commit` was renamed to `save`, the old name is kept as an alias",
+		"git: subcommand `log` was added",
+	}, "Wrong additions reported")
+
+	assert(t, !report.IsCompatible(), "Breaking changes not detected")
+	summary := report.String()
+	assert(t, strings.HasPrefix(summary, "BREAKING CHANGES (11):\n"), "Wrong report summary")
+	assert(t, strings.Contains(summary, "ADDITIONS (5):\n"), "Additions missing from report summary")
+}
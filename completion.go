@@ -0,0 +1,862 @@
+package gommander
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Shell represents a shell for which completion scripts can be generated
+type Shell string
+
+const (
+	Bash       Shell = "bash"
+	Zsh        Shell = "zsh"
+	Fish       Shell = "fish"
+	PowerShell Shell = "powershell"
+)
+
+// A callback used to provide completion candidates for an argument or option at runtime. It receives the matches of the partial command line and the value being completed
+type CompletionCallback = func(pm *ParserMatches, toComplete string) []string
+
+var supportedShells = []string{string(Bash), string(Zsh), string(Fish), string(PowerShell)}
+
+// The name of the hidden entry point invoked by the dynamic completion scripts
+const completeCmdName = "__complete"
+
+// Directives written out on the last line of the dynamic completion output
+const (
+	completeDefault = iota
+	completeFiles
+)
+
+var nonIdentChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+type completionWord struct {
+	value string
+	help  string
+}
+
+type completionOpt struct {
+	switches []string
+	values   []string
+	files    bool
+	help     string
+	option   *Option
+}
+
+type completionNode struct {
+	id          string
+	cmd         *Command
+	transitions map[string]string
+	subCmds     []completionWord
+	words       []completionWord
+	opts        []completionOpt
+	argValues   []string
+	argFiles    bool
+}
+
+type completionGen struct {
+	binName string
+	ident   string
+	nodes   []*completionNode
+}
+
+// Writes a completion script for the given shell to the provided writer. The script is generated from the command tree, i.e. subcommands, aliases, flags, options and the valid values of arguments
+func (c *Command) GenerateCompletion(shell Shell, w io.Writer) error {
+	gen := newCompletionGen(c)
+
+	var script string
+	switch shell {
+	case Bash:
+		script = gen.bash()
+	case Zsh:
+		script = gen.zsh()
+	case Fish:
+		script = gen.fish()
+	case PowerShell:
+		script = gen.powershell()
+	default:
+		return fmt.Errorf("unsupported shell: `%v`, expected one of: `[%v]`", shell, strings.Join(supportedShells, ", "))
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// Writes a completion script for the given shell that calls back into the program binary via the hidden `__complete` entry point, so completions always match the running version. The entry point is handled whether or not the `IncludeCompletionSubcommand` setting is enabled. Values provided by completion callbacks are only available through these scripts
+func (c *Command) GenerateDynamicCompletion(shell Shell, w io.Writer) error {
+	gen := newCompletionGen(c)
+
+	var script string
+	switch shell {
+	case Bash:
+		script = gen.dynamicBash()
+	case Zsh:
+		script = gen.dynamicZsh()
+	case Fish:
+		script = gen.dynamicFish()
+	case PowerShell:
+		script = gen.dynamicPowershell()
+	default:
+		return fmt.Errorf("unsupported shell: `%v`, expected one of: `[%v]`", shell, strings.Join(supportedShells, ", "))
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+func completionSubCmd() *Command {
+	return NewCommand("completion").
+		Help("Generate a shell completion script").
+		Hidden(true).
+		builtin().
+		AddArgument(
+			NewArgument("<shell>").
+				Help("The shell to generate the completion script for").
+				ValidateWith(supportedShells),
+		).
+		Flag("-d --dynamic", "Generate a script that queries the program for completions at runtime").
+		Action(func(pm *ParserMatches) {
+			val, _ := pm.GetArgValue("<shell>")
+			app := pm.GetAppRef()
+			shell := Shell(strings.ToLower(val))
+
+			var err error
+			if pm.ContainsFlag("dynamic") {
+				err = app.GenerateDynamicCompletion(shell, os.Stdout)
+			} else {
+				err = app.GenerateCompletion(shell, os.Stdout)
+			}
+
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		})
+}
+
+/****************************** Runtime completion ****************************/
+
+// Resolves the completion candidates for a partial command line. The last value in args is the one being completed. Candidates are written out one per line, optionally followed by a tab and their help string, and the last line contains the completion directive
+func (c *Command) complete(args []string, w io.Writer) {
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	// Some shells cannot pass empty arguments to native commands
+	if toComplete == `""` {
+		toComplete = ""
+	}
+
+	parser := NewParser(c)
+	matches, _ := parser.parse(args)
+	cmd := matches.GetMatchedCommand()
+
+	candidates, directive := cmd.completionCandidates(matches, args[matches.GetMatchedCommandIndex()+1:], toComplete)
+	for _, cand := range candidates {
+		if len(cand.help) > 0 {
+			fmt.Fprintf(w, "%v\t%v\n", cand.value, cand.help)
+		} else {
+			fmt.Fprintln(w, cand.value)
+		}
+	}
+	fmt.Fprintf(w, ":%v\n", directive)
+}
+
+func (c *Command) completionCandidates(pm *ParserMatches, args []string, toComplete string) ([]completionWord, int) {
+	words := []completionWord{}
+	matchPrefix := func(prefix string, values []string, help string) {
+		for _, v := range values {
+			if strings.HasPrefix(v, toComplete) {
+				words = append(words, completionWord{prefix + v, help})
+			}
+		}
+	}
+
+	// option values passed using the `--opt=value` syntax
+	if strings.HasPrefix(toComplete, "--") && strings.ContainsRune(toComplete, '=') {
+		parts := strings.SplitN(toComplete, "=", 2)
+		if opt, err := c.findOption(parts[0]); err == nil {
+			values, directive := opt.completeValue(pm, parts[1])
+			toComplete = parts[1]
+			matchPrefix(parts[0]+"=", values, "")
+			return words, directive
+		}
+		return words, completeDefault
+	}
+
+	// the value of the previous option
+	if len(args) > 0 && !strings.HasPrefix(toComplete, "-") {
+		if opt, err := c.findOption(args[len(args)-1]); err == nil && opt.Arg != nil {
+			values, directive := opt.completeValue(pm, toComplete)
+			matchPrefix("", values, "")
+			return words, directive
+		}
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		for _, f := range c.allFlags() {
+			matchPrefix("", f.switches(), f.HelpStr)
+		}
+		for _, o := range c.allOptions() {
+			matchPrefix("", []string{o.ShortVal, o.LongVal}, o.HelpStr)
+		}
+		return words, completeDefault
+	}
+
+	directive := completeDefault
+	positionalIdx := c.positionalCount(args)
+
+	if positionalIdx == 0 {
+		for _, sc := range c.visibleSubCommands() {
+			matchPrefix("", append([]string{sc.name}, sc.aliases...), sc.help)
+		}
+	}
+
+	if arg := c.argumentAt(positionalIdx); arg != nil {
+		values, d := arg.completeValue(pm, toComplete)
+		matchPrefix("", values, "")
+		directive = d
+	}
+
+	return words, directive
+}
+
+// Counts the positional values in args, skipping flags, options and their values
+func (c *Command) positionalCount(args []string) int {
+	count := 0
+	for i := 0; i < len(args); i++ {
+		v := args[i]
+		if strings.HasPrefix(v, "-") {
+			if opt, err := c.findOption(v); err == nil && opt.Arg != nil {
+				i++
+			}
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+func (c *Command) argumentAt(idx int) *Argument {
+	if idx < len(c.arguments) {
+		return c.arguments[idx]
+	}
+	if len(c.arguments) > 0 && c.arguments[len(c.arguments)-1].IsVariadic {
+		return c.arguments[len(c.arguments)-1]
+	}
+	return nil
+}
+
+func (o *Option) completeValue(pm *ParserMatches, toComplete string) ([]string, int) {
+	if o.CompletionFn != nil {
+		return o.CompletionFn(pm, toComplete), completeDefault
+	}
+	if o.Arg != nil {
+		return o.Arg.completeValue(pm, toComplete)
+	}
+	return []string{}, completeDefault
+}
+
+func (a *Argument) completeValue(pm *ParserMatches, toComplete string) ([]string, int) {
+	if a.CompletionFn != nil {
+		return a.CompletionFn(pm, toComplete), completeDefault
+	}
+	if len(a.ValidValues) > 0 {
+		return a.ValidValues, completeDefault
+	}
+	if a.ArgType == filename {
+		return []string{}, completeFiles
+	}
+	return []string{}, completeDefault
+}
+
+/****************************** Command tree traversal ****************************/
+
+func newCompletionGen(c *Command) *completionGen {
+	root := c._getAppRef()
+	if root == nil {
+		root = c
+	}
+
+	binName := root.name
+	if len(binName) == 0 {
+		binName = filepath.Base(os.Args[0])
+	}
+
+	ident := nonIdentChars.ReplaceAllString(binName, "_")
+	gen := completionGen{binName: binName, ident: ident}
+	gen.walk(root, ident)
+
+	return &gen
+}
+
+func (g *completionGen) walk(c *Command, id string) {
+	node := completionNode{
+		id:          id,
+		cmd:         c,
+		transitions: make(map[string]string),
+	}
+	g.nodes = append(g.nodes, &node)
+
+	for _, sc := range c.subCommands {
+		if sc.hidden {
+			continue
+		}
+
+		childID := id + "__" + nonIdentChars.ReplaceAllString(sc.name, "_")
+		for _, v := range append([]string{sc.name}, sc.aliases...) {
+			node.transitions[v] = childID
+			node.subCmds = append(node.subCmds, completionWord{v, sc.help})
+		}
+	}
+
+	node.words = append(node.words, node.subCmds...)
+
+	for _, f := range c.allFlags() {
+		for _, v := range f.switches() {
+			node.words = append(node.words, completionWord{v, f.HelpStr})
+		}
+	}
+
+	for _, o := range c.allOptions() {
+		opt := completionOpt{help: o.HelpStr, option: o}
+		for _, v := range []string{o.ShortVal, o.LongVal} {
+			if len(v) > 0 {
+				opt.switches = append(opt.switches, v)
+				node.words = append(node.words, completionWord{v, o.HelpStr})
+			}
+		}
+
+		if o.Arg != nil {
+			opt.values = o.Arg.ValidValues
+			opt.files = len(o.Arg.ValidValues) == 0 && (o.Arg.ArgType == str || o.Arg.ArgType == filename)
+		}
+		node.opts = append(node.opts, opt)
+	}
+
+	for _, a := range c.arguments {
+		node.argValues = append(node.argValues, a.ValidValues...)
+		if a.ArgType == filename {
+			node.argFiles = true
+		}
+	}
+	for _, v := range node.argValues {
+		node.words = append(node.words, completionWord{v, ""})
+	}
+
+	for _, sc := range c.subCommands {
+		if !sc.hidden {
+			g.walk(sc, id+"__"+nonIdentChars.ReplaceAllString(sc.name, "_"))
+		}
+	}
+}
+
+// Returns the subcommand transitions of a node in a deterministic order
+func (n *completionNode) orderedTransitions() [][2]string {
+	values := [][2]string{}
+	for _, w := range n.subCmds {
+		values = append(values, [2]string{w.value, n.transitions[w.value]})
+	}
+	return values
+}
+
+/****************************** Quoting utilities ****************************/
+
+func bashQuote(val string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return `"` + replacer.Replace(val) + `"`
+}
+
+func singleQuote(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", `'\''`) + "'"
+}
+
+func fishQuote(val string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + replacer.Replace(val) + "'"
+}
+
+func psQuote(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+}
+
+func zshDescribe(w completionWord) string {
+	value := strings.ReplaceAll(w.value, ":", `\:`)
+	if len(w.help) == 0 {
+		return singleQuote(value)
+	}
+	return singleQuote(fmt.Sprintf("%v:%v", value, w.help))
+}
+
+func joinWords(words []completionWord) string {
+	values := []string{}
+	for _, w := range words {
+		values = append(values, w.value)
+	}
+	return strings.Join(values, " ")
+}
+
+/****************************** Bash ****************************/
+
+func (g *completionGen) bash() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# bash completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "_%v_completions() {\n", g.ident)
+	b.WriteString("    local cur prev cmd i\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(&b, "    cmd=%v\n", bashQuote(g.ident))
+	b.WriteString("    COMPREPLY=()\n")
+
+	var transitions strings.Builder
+	for _, n := range g.nodes {
+		for _, t := range n.orderedTransitions() {
+			fmt.Fprintf(&transitions, "            %v)\n", bashQuote(n.id+","+t[0]))
+			fmt.Fprintf(&transitions, "                cmd=%v\n", bashQuote(t[1]))
+			transitions.WriteString("                ;;\n")
+		}
+	}
+	if transitions.Len() > 0 {
+		b.WriteString("\n    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+		b.WriteString("        case \"${cmd},${COMP_WORDS[i]}\" in\n")
+		b.WriteString(transitions.String())
+		b.WriteString("        esac\n")
+		b.WriteString("    done\n")
+	}
+
+	var values strings.Builder
+	for _, n := range g.nodes {
+		for _, o := range n.opts {
+			if o.option.Arg == nil {
+				continue
+			}
+
+			patterns := []string{}
+			for _, s := range o.switches {
+				patterns = append(patterns, bashQuote(n.id+","+s))
+			}
+			fmt.Fprintf(&values, "        %v)\n", strings.Join(patterns, " | "))
+
+			if len(o.values) > 0 {
+				fmt.Fprintf(&values, "            COMPREPLY=($(compgen -W %v -- \"${cur}\"))\n", bashQuote(strings.Join(o.values, " ")))
+			} else if o.files {
+				values.WriteString("            COMPREPLY=($(compgen -f -- \"${cur}\"))\n")
+			}
+			values.WriteString("            return 0\n")
+			values.WriteString("            ;;\n")
+		}
+	}
+	if values.Len() > 0 {
+		b.WriteString("\n    case \"${cmd},${prev}\" in\n")
+		b.WriteString(values.String())
+		b.WriteString("    esac\n")
+	}
+
+	b.WriteString("\n    case \"${cmd}\" in\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&b, "        %v)\n", bashQuote(n.id))
+		fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W %v -- \"${cur}\"))\n", bashQuote(joinWords(n.words)))
+		if n.argFiles {
+			b.WriteString("            COMPREPLY+=($(compgen -f -- \"${cur}\"))\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    return 0\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "complete -F _%v_completions %v\n", g.ident, g.binName)
+
+	return b.String()
+}
+
+/****************************** Zsh ****************************/
+
+func (g *completionGen) zsh() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "#compdef %v\n", g.binName)
+	fmt.Fprintf(&b, "# zsh completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "_%v() {\n", g.ident)
+	fmt.Fprintf(&b, "    local cmd=%v prev=\"${words[CURRENT-1]}\" i\n", singleQuote(g.ident))
+	b.WriteString("    local -a opts\n")
+
+	var transitions strings.Builder
+	for _, n := range g.nodes {
+		for _, t := range n.orderedTransitions() {
+			fmt.Fprintf(&transitions, "            %v)\n", singleQuote(n.id+","+t[0]))
+			fmt.Fprintf(&transitions, "                cmd=%v\n", singleQuote(t[1]))
+			transitions.WriteString("                ;;\n")
+		}
+	}
+	if transitions.Len() > 0 {
+		b.WriteString("\n    for ((i = 2; i < CURRENT; i++)); do\n")
+		b.WriteString("        case \"${cmd},${words[i]}\" in\n")
+		b.WriteString(transitions.String())
+		b.WriteString("        esac\n")
+		b.WriteString("    done\n")
+	}
+
+	var values strings.Builder
+	for _, n := range g.nodes {
+		for _, o := range n.opts {
+			if o.option.Arg == nil {
+				continue
+			}
+
+			patterns := []string{}
+			for _, s := range o.switches {
+				patterns = append(patterns, singleQuote(n.id+","+s))
+			}
+			fmt.Fprintf(&values, "        %v)\n", strings.Join(patterns, "|"))
+
+			if len(o.values) > 0 {
+				quoted := []string{}
+				for _, v := range o.values {
+					quoted = append(quoted, singleQuote(v))
+				}
+				fmt.Fprintf(&values, "            compadd -- %v\n", strings.Join(quoted, " "))
+			} else if o.files {
+				values.WriteString("            _files\n")
+			}
+			values.WriteString("            return\n")
+			values.WriteString("            ;;\n")
+		}
+	}
+	if values.Len() > 0 {
+		b.WriteString("\n    case \"${cmd},${prev}\" in\n")
+		b.WriteString(values.String())
+		b.WriteString("    esac\n")
+	}
+
+	b.WriteString("\n    case \"${cmd}\" in\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&b, "        %v)\n", singleQuote(n.id))
+		b.WriteString("            opts=(\n")
+		for _, w := range n.words {
+			fmt.Fprintf(&b, "                %v\n", zshDescribe(w))
+		}
+		b.WriteString("            )\n")
+		if n.argFiles {
+			b.WriteString("            _files\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	fmt.Fprintf(&b, "    _describe -t values %v opts\n", singleQuote(g.binName))
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "if [ \"$funcstack[1]\" = \"_%v\" ]; then\n", g.ident)
+	fmt.Fprintf(&b, "    _%v \"$@\"\n", g.ident)
+	b.WriteString("else\n")
+	fmt.Fprintf(&b, "    compdef _%v %v\n", g.ident, g.binName)
+	b.WriteString("fi\n")
+
+	return b.String()
+}
+
+/****************************** Fish ****************************/
+
+func (g *completionGen) fish() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# fish completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "function __%v_cmd\n", g.ident)
+	fmt.Fprintf(&b, "    set -l cmd %v\n", fishQuote(g.ident))
+
+	var transitions strings.Builder
+	for _, n := range g.nodes {
+		for _, t := range n.orderedTransitions() {
+			fmt.Fprintf(&transitions, "            case %v\n", fishQuote(n.id+","+t[0]))
+			fmt.Fprintf(&transitions, "                set cmd %v\n", fishQuote(t[1]))
+		}
+	}
+	if transitions.Len() > 0 {
+		b.WriteString("    for word in (commandline -opc)[2..-1]\n")
+		b.WriteString("        switch \"$cmd,$word\"\n")
+		b.WriteString(transitions.String())
+		b.WriteString("        end\n")
+		b.WriteString("    end\n")
+	}
+	b.WriteString("    echo $cmd\n")
+	b.WriteString("end\n\n")
+
+	fmt.Fprintf(&b, "complete -c %v -f\n", g.binName)
+
+	for _, n := range g.nodes {
+		cond := fishQuote(fmt.Sprintf("test (__%v_cmd) = %v", g.ident, n.id))
+		prefix := fmt.Sprintf("complete -c %v -n %v", g.binName, cond)
+
+		for _, w := range n.subCmds {
+			b.WriteString(prefix)
+			fmt.Fprintf(&b, " -a %v", fishQuote(w.value))
+			if len(w.help) > 0 {
+				fmt.Fprintf(&b, " -d %v", fishQuote(w.help))
+			}
+			b.WriteString("\n")
+		}
+
+		for _, f := range n.cmd.allFlags() {
+			b.WriteString(prefix)
+			b.WriteString(fishSwitches(f.ShortVal, f.LongVal))
+			if len(f.HelpStr) > 0 {
+				fmt.Fprintf(&b, " -d %v", fishQuote(f.HelpStr))
+			}
+			b.WriteString("\n")
+
+			if negated := f.negatedLongVal(); len(negated) > 0 {
+				b.WriteString(prefix)
+				b.WriteString(fishSwitches("", negated))
+				if len(f.HelpStr) > 0 {
+					fmt.Fprintf(&b, " -d %v", fishQuote(f.HelpStr))
+				}
+				b.WriteString("\n")
+			}
+		}
+
+		for _, o := range n.opts {
+			b.WriteString(prefix)
+			b.WriteString(fishSwitches(o.option.ShortVal, o.option.LongVal))
+			if o.option.Arg != nil {
+				b.WriteString(" -r")
+				if len(o.values) > 0 {
+					fmt.Fprintf(&b, " -a %v", fishQuote(strings.Join(o.values, " ")))
+				} else if o.files {
+					b.WriteString(" -F")
+				}
+			}
+			if len(o.help) > 0 {
+				fmt.Fprintf(&b, " -d %v", fishQuote(o.help))
+			}
+			b.WriteString("\n")
+		}
+
+		if len(n.argValues) > 0 {
+			b.WriteString(prefix)
+			fmt.Fprintf(&b, " -a %v\n", fishQuote(strings.Join(n.argValues, " ")))
+		}
+		if n.argFiles {
+			b.WriteString(prefix)
+			b.WriteString(" -F\n")
+		}
+	}
+
+	return b.String()
+}
+
+func fishSwitches(short, long string) string {
+	var b strings.Builder
+
+	if len(short) == 2 {
+		fmt.Fprintf(&b, " -s %v", strings.TrimPrefix(short, "-"))
+	} else if len(short) > 0 {
+		fmt.Fprintf(&b, " -o %v", strings.TrimPrefix(short, "-"))
+	}
+	if len(long) > 0 {
+		fmt.Fprintf(&b, " -l %v", strings.TrimPrefix(long, "--"))
+	}
+
+	return b.String()
+}
+
+/****************************** PowerShell ****************************/
+
+func (g *completionGen) powershell() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# powershell completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName %v -ScriptBlock {\n", psQuote(g.binName))
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	fmt.Fprintf(&b, "    $cmd = %v\n", psQuote(g.ident))
+	b.WriteString("    $prev = ''\n")
+	b.WriteString("    $values = $null\n\n")
+	b.WriteString("    foreach ($element in $commandAst.CommandElements | Select-Object -Skip 1) {\n")
+	b.WriteString("        if ($element.Extent.EndOffset -ge $cursorPosition) {\n")
+	b.WriteString("            break\n")
+	b.WriteString("        }\n")
+	b.WriteString("        $word = $element.ToString()\n")
+	b.WriteString("        switch -CaseSensitive (\"$cmd,$word\") {\n")
+	for _, n := range g.nodes {
+		for _, t := range n.orderedTransitions() {
+			fmt.Fprintf(&b, "            %v { $cmd = %v }\n", psQuote(n.id+","+t[0]), psQuote(t[1]))
+		}
+	}
+	b.WriteString("        }\n")
+	b.WriteString("        $prev = $word\n")
+	b.WriteString("    }\n\n")
+
+	b.WriteString("    switch -CaseSensitive (\"$cmd,$prev\") {\n")
+	for _, n := range g.nodes {
+		for _, o := range n.opts {
+			if o.option.Arg == nil {
+				continue
+			}
+
+			for _, s := range o.switches {
+				if len(o.values) > 0 {
+					quoted := []string{}
+					for _, v := range o.values {
+						quoted = append(quoted, psQuote(v))
+					}
+					fmt.Fprintf(&b, "        %v { $values = @(%v) }\n", psQuote(n.id+","+s), strings.Join(quoted, ", "))
+				} else {
+					fmt.Fprintf(&b, "        %v { return }\n", psQuote(n.id+","+s))
+				}
+			}
+		}
+	}
+	b.WriteString("    }\n\n")
+
+	b.WriteString("    if ($null -ne $values) {\n")
+	b.WriteString("        $values | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	b.WriteString("            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	b.WriteString("        }\n")
+	b.WriteString("        return\n")
+	b.WriteString("    }\n\n")
+
+	b.WriteString("    $candidates = switch -CaseSensitive ($cmd) {\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&b, "        %v {\n", psQuote(n.id))
+		for _, w := range n.words {
+			help := w.help
+			if len(help) == 0 {
+				help = w.value
+			}
+			fmt.Fprintf(&b, "            [pscustomobject]@{ Name = %v; Help = %v }\n", psQuote(w.value), psQuote(help))
+		}
+		b.WriteString("        }\n")
+	}
+	b.WriteString("    }\n\n")
+
+	b.WriteString("    $candidates | Where-Object { $_.Name -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	b.WriteString("        [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ParameterValue', $_.Help)\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+/****************************** Dynamic scripts ****************************/
+
+func (g *completionGen) dynamicBash() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# bash completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "_%v_completions() {\n", g.ident)
+	b.WriteString("    local cur out directive line IFS=$'\\n'\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(&b, "    out=$(\"${COMP_WORDS[0]}\" %v \"${COMP_WORDS[@]:1:COMP_CWORD-1}\" \"${cur}\" 2>/dev/null) || return\n", completeCmdName)
+	b.WriteString("    directive=\"${out##*:}\"\n")
+	b.WriteString("    out=\"${out%:*}\"\n")
+	b.WriteString("    COMPREPLY=()\n\n")
+	b.WriteString("    for line in ${out}; do\n")
+	b.WriteString("        COMPREPLY+=(\"${line%%$'\\t'*}\")\n")
+	b.WriteString("    done\n\n")
+	fmt.Fprintf(&b, "    if [ \"${directive}\" = \"%v\" ]; then\n", completeFiles)
+	b.WriteString("        COMPREPLY+=($(compgen -f -- \"${cur}\"))\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    return 0\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "complete -F _%v_completions %v\n", g.ident, g.binName)
+
+	return b.String()
+}
+
+func (g *completionGen) dynamicZsh() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "#compdef %v\n", g.binName)
+	fmt.Fprintf(&b, "# zsh completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "_%v() {\n", g.ident)
+	b.WriteString("    local out directive line\n")
+	b.WriteString("    local -a lines candidates\n\n")
+	fmt.Fprintf(&b, "    out=$(\"${words[1]}\" %v \"${(@)words[2,CURRENT]}\" 2>/dev/null) || return\n", completeCmdName)
+	b.WriteString("    lines=(\"${(@f)out}\")\n")
+	b.WriteString("    directive=\"${lines[-1]#:}\"\n\n")
+	b.WriteString("    for line in \"${(@)lines[1,-2]}\"; do\n")
+	b.WriteString("        if [[ \"${line}\" == *$'\\t'* ]]; then\n")
+	b.WriteString("            candidates+=(\"${${line%%$'\\t'*}//:/\\\\:}:${line#*$'\\t'}\")\n")
+	b.WriteString("        else\n")
+	b.WriteString("            candidates+=(\"${line//:/\\\\:}\")\n")
+	b.WriteString("        fi\n")
+	b.WriteString("    done\n\n")
+	fmt.Fprintf(&b, "    _describe -t values %v candidates\n", singleQuote(g.binName))
+	fmt.Fprintf(&b, "    if [ \"${directive}\" = \"%v\" ]; then\n", completeFiles)
+	b.WriteString("        _files\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "if [ \"$funcstack[1]\" = \"_%v\" ]; then\n", g.ident)
+	fmt.Fprintf(&b, "    _%v \"$@\"\n", g.ident)
+	b.WriteString("else\n")
+	fmt.Fprintf(&b, "    compdef _%v %v\n", g.ident, g.binName)
+	b.WriteString("fi\n")
+
+	return b.String()
+}
+
+func (g *completionGen) dynamicFish() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# fish completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "function __%v_complete\n", g.ident)
+	b.WriteString("    set -l words (commandline -opc)\n")
+	b.WriteString("    set -l current (commandline -ct)\n")
+	fmt.Fprintf(&b, "    set -l out ($words[1] %v $words[2..-1] \"$current\" 2>/dev/null)\n", completeCmdName)
+	b.WriteString("    set -l directive $out[-1]\n")
+	b.WriteString("    set -e out[-1]\n\n")
+	b.WriteString("    for line in $out\n")
+	b.WriteString("        echo $line\n")
+	b.WriteString("    end\n\n")
+	fmt.Fprintf(&b, "    if test \"$directive\" = \":%v\"\n", completeFiles)
+	b.WriteString("        __fish_complete_path \"$current\"\n")
+	b.WriteString("    end\n")
+	b.WriteString("end\n\n")
+
+	fmt.Fprintf(&b, "complete -c %v -f -a %v\n", g.binName, fishQuote(fmt.Sprintf("(__%v_complete)", g.ident)))
+
+	return b.String()
+}
+
+func (g *completionGen) dynamicPowershell() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# powershell completion for %v\n", g.binName)
+	b.WriteString("# Generated by gommander. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName %v -ScriptBlock {\n", psQuote(g.binName))
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	b.WriteString("    $program = $commandAst.CommandElements[0].ToString()\n")
+	b.WriteString("    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })\n")
+	b.WriteString("    if ($wordToComplete -eq '') {\n")
+	b.WriteString("        $words += '\"\"'\n")
+	b.WriteString("    } else {\n")
+	b.WriteString("        $words += $wordToComplete\n")
+	b.WriteString("    }\n\n")
+	fmt.Fprintf(&b, "    $out = @(& $program %v @words 2>$null)\n", completeCmdName)
+	b.WriteString("    if ($out.Count -eq 0) {\n")
+	b.WriteString("        return\n")
+	b.WriteString("    }\n\n")
+	b.WriteString("    $out | Select-Object -SkipLast 1 | ForEach-Object {\n")
+	b.WriteString("        $parts = $_ -split \"`t\", 2\n")
+	b.WriteString("        $help = if ($parts.Count -gt 1 -and $parts[1]) { $parts[1] } else { $parts[0] }\n")
+	b.WriteString("        [System.Management.Automation.CompletionResult]::new($parts[0], $parts[0], 'ParameterValue', $help)\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
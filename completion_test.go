@@ -0,0 +1,162 @@
+package gommander
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+func TestCompletionGolden(t *testing.T) {
+	app := App().Name("docker").Version("0.1.0").Help("A simple docker example")
+
+	image := app.SubCommand("image").Alias("i").Help("Manage images")
+	image.SubCommand("ls").
+		Help("List images").
+		Flag("-a --all", "Show all images").
+		AddOption(
+			NewOption("format").
+				Help("Format output using a template").
+				AddArgument(NewArgument("<fmt>").ValidateWith([]string{"json", "table"})),
+		)
+	image.SubCommand("build").
+		Help("Build an image from a Dockerfile").
+		Argument("<file:path>", "The path to the build context").
+		Option("-f --file <string>", "Name of the Dockerfile")
+
+	app.SubCommand("run").
+		Help("Run a command in a new container").
+		Option("-c --cpus <int:count>", "Number of CPUs").
+		AddOption(
+			NewOption("name").
+				Help("Assign a name to the container").
+				Argument("<name>").
+				CompletionFunc(func(pm *ParserMatches, toComplete string) []string {
+					return []string{"web", "worker", "db"}
+				}),
+		).
+		AddArgument(NewArgument("<mode>").ValidateWith([]string{"attached", "detached"})).
+		AddArgument(NewArgument("[config]").Type(filename))
+
+	app.Set(IncludeCompletionSubcommand, true)
+	app._init()
+
+	for _, shell := range []Shell{Bash, Zsh, Fish, PowerShell} {
+		var static, dynamic bytes.Buffer
+		if err := app.GenerateCompletion(shell, &static); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.GenerateDynamicCompletion(shell, &dynamic); err != nil {
+			t.Fatal(err)
+		}
+
+		_assertGolden(t, filepath.Join("testdata", "completion", string(shell)+".golden"), static.String())
+		_assertGolden(t, filepath.Join("testdata", "completion", string(shell)+".dynamic.golden"), dynamic.String())
+	}
+}
+
+func _assertGolden(t *testing.T, golden string, got string) {
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, got, string(expected), "Output does not match golden file: ", golden)
+}
+
+func _assertCompletions(t *testing.T, app *Command, args []string, expected string) {
+	var buf bytes.Buffer
+	app.complete(args, &buf)
+	assertEq(t, buf.String(), expected, "Runtime completion failed for args: ", args)
+}
+
+func TestRuntimeCompletion(t *testing.T) {
+	app := App().Name("docker").Version("0.1.0").Help("A simple docker example")
+
+	image := app.SubCommand("image").Alias("i").Help("Manage images")
+	image.SubCommand("ls").
+		Help("List images").
+		Flag("-a --all", "Show all images").
+		AddOption(
+			NewOption("format").
+				Help("Format output using a template").
+				AddArgument(NewArgument("<fmt>").ValidateWith([]string{"json", "table"})),
+		)
+	image.SubCommand("build").Help("Build an image from a Dockerfile")
+
+	app.SubCommand("run").
+		Help("Run a command in a new container").
+		Option("-c --cpus <int:count>", "Number of CPUs").
+		AddOption(
+			NewOption("name").
+				Help("Assign a name to the container").
+				Argument("<name>").
+				CompletionFunc(func(pm *ParserMatches, toComplete string) []string {
+					return []string{"web", "worker", "db"}
+				}),
+		).
+		AddArgument(NewArgument("<mode>").ValidateWith([]string{"attached", "detached"})).
+		AddArgument(NewArgument("[config]").Type(filename))
+	app._init()
+
+	// subcommands and aliases
+	_assertCompletions(t, app, []string{""}, "image\tManage images\ni\tManage images\nrun\tRun a command in a new container\n:0\n")
+	_assertCompletions(t, app, []string{"image", "b"}, "build\tBuild an image from a Dockerfile\n:0\n")
+
+	// flags and options
+	_assertCompletions(t, app, []string{"image", "ls", "--"}, "--help\tPrint out help information\n--all\tShow all images\n--format\tFormat output using a template\n:0\n")
+
+	// option values from valid values, including the `=` syntax
+	_assertCompletions(t, app, []string{"i", "ls", "--format", "j"}, "json\n:0\n")
+	_assertCompletions(t, app, []string{"i", "ls", "--format=t"}, "--format=table\n:0\n")
+
+	// option values from completion callbacks
+	_assertCompletions(t, app, []string{"run", "--name", "w"}, "web\nworker\n:0\n")
+
+	// argument values, then file paths for file arguments
+	_assertCompletions(t, app, []string{"run", "-c", "2", "d"}, "detached\n:0\n")
+	_assertCompletions(t, app, []string{"run", "attached", ""}, ":1\n")
+}
+
+func TestCompletionSubcommand(t *testing.T) {
+	app := App().Name("docker").Set(IncludeCompletionSubcommand, true)
+	app.SubCommand("run").Help("Run a command in a new container")
+	app._init()
+
+	sc, err := app.findSubcommand("completion")
+	assertEq(t, err, nil, "Completion subcommand not added by setting")
+	assert(t, sc.hidden, "Completion subcommand should be hidden")
+
+	for _, v := range app.visibleSubCommands() {
+		assertNe(t, v.name, "completion", "Hidden completion subcommand listed as visible")
+	}
+
+	var buf bytes.Buffer
+	err = app.GenerateCompletion(Shell("tcsh"), &buf)
+	assertNe(t, err, nil, "Unsupported shells should return an error")
+}
+
+func TestCompleteEntryPoint(t *testing.T) {
+	// the entry point does not depend on the completion subcommand being enabled
+	app := App().Name("docker")
+	app.SubCommand("run").AddOption(
+		NewOption("name").
+			Argument("<name>").
+			CompletionFunc(func(pm *ParserMatches, toComplete string) []string {
+				return []string{"web", "worker", "db"}
+			}),
+	)
+
+	exec := func() {
+		app.ParseFrom([]string{"docker", "__complete", "run", "--name", "d"})
+	}
+	assertStdOut(t, "db\n:0\n", exec, "The hidden __complete entry point failed")
+}
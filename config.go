@@ -0,0 +1,291 @@
+package gommander
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Represents the layer from which the value of an option or argument was acquired
+type ValueSource byte
+
+const (
+	// The value was passed to the program in the command line
+	SourceCommandLine ValueSource = iota
+	// The value was read from an environment variable
+	SourceEnv
+	// The value was read from the configuration file
+	SourceConfig
+	// The default value of the argument was used
+	SourceDefault
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceCommandLine:
+		return "command line"
+	case SourceEnv:
+		return "environment"
+	case SourceConfig:
+		return "config file"
+	case SourceDefault:
+		return "default"
+	}
+	return "unknown"
+}
+
+// Flattened configuration values. Keys of nested sections are joined using a `.`, i.e. the `region` key in the `deploy` section is stored as `deploy.region`
+type configValues = map[string][]string
+
+var configExtensions = []string{".json", ".toml", ".ini", ".yaml", ".yml"}
+
+// Enables loading option values from a configuration file. The file is read from the path passed to the `--config` option that this method adds to the command, or from the first file named `config` with a supported extension in the XDG config directories, i.e. `$XDG_CONFIG_HOME/<name>/config.json`. The `--config` option is global and can be passed after the names of subcommands.
+// Supported formats are JSON, TOML-like INI and a subset of YAML. Keys map to the long names of options and sections map to subcommands
+func (c *Command) ConfigFile(name string) *Command {
+	c.configName = name
+	return c.AddOption(
+		NewOption("config").
+			Help("Path to the configuration file").
+			Global(true).
+			AddArgument(NewArgument("<file:path>")),
+	)
+}
+
+/****************************** Config file lookup ****************************/
+
+func configSearchDirs() []string {
+	dirs := []string{}
+
+	if home, exists := os.LookupEnv("XDG_CONFIG_HOME"); exists && len(home) > 0 {
+		dirs = append(dirs, home)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config"))
+	}
+
+	if others, exists := os.LookupEnv("XDG_CONFIG_DIRS"); exists && len(others) > 0 {
+		dirs = append(dirs, filepath.SplitList(others)...)
+	} else {
+		dirs = append(dirs, "/etc/xdg")
+	}
+
+	return dirs
+}
+
+func findConfigFile(name string) string {
+	for _, dir := range configSearchDirs() {
+		for _, ext := range configExtensions {
+			path := filepath.Join(dir, name, "config"+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+func loadConfigFile(path string) (configValues, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return parseJSONConfig(data)
+	case ".yaml", ".yml":
+		return parseYAMLConfig(data)
+	case ".toml", ".ini", ".conf", "":
+		return parseINIConfig(data)
+	}
+
+	return nil, fmt.Errorf("unsupported config file format: `%v`", filepath.Ext(path))
+}
+
+/****************************** Config parsers ****************************/
+
+func parseJSONConfig(data []byte) (configValues, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	values := make(configValues)
+	var flatten func(prefix string, obj map[string]interface{}) error
+	flatten = func(prefix string, obj map[string]interface{}) error {
+		for k, v := range obj {
+			key := joinConfigKey(prefix, k)
+
+			switch val := v.(type) {
+			case map[string]interface{}:
+				if err := flatten(key, val); err != nil {
+					return err
+				}
+			case []interface{}:
+				for _, item := range val {
+					if _, isObj := item.(map[string]interface{}); isObj {
+						return fmt.Errorf("unsupported nested object in list: `%v`", key)
+					}
+					values[key] = append(values[key], fmt.Sprint(item))
+				}
+			case nil:
+				continue
+			default:
+				values[key] = []string{fmt.Sprint(val)}
+			}
+		}
+		return nil
+	}
+
+	if err := flatten("", raw); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func parseINIConfig(data []byte) (configValues, error) {
+	values := make(configValues)
+	section := ""
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx < 1 {
+			return nil, fmt.Errorf("line %v: expected a key-value pair but found: `%v`", i+1, line)
+		}
+
+		key := joinConfigKey(section, strings.Trim(strings.TrimSpace(line[:idx]), `"'`))
+		values[key] = parseConfigValue(line[idx+1:])
+	}
+
+	return values, nil
+}
+
+func parseYAMLConfig(data []byte) (configValues, error) {
+	type level struct {
+		indent int
+		prefix string
+	}
+
+	values := make(configValues)
+	stack := []level{{-1, ""}}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(stripConfigComment(line))
+		if len(trimmed) == 0 || trimmed == "---" {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		isListItem := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+
+		for len(stack) > 1 {
+			top := stack[len(stack)-1]
+			if indent < top.indent || (indent == top.indent && !isListItem) {
+				stack = stack[:len(stack)-1]
+			} else {
+				break
+			}
+		}
+		prefix := stack[len(stack)-1].prefix
+
+		if isListItem {
+			if len(prefix) == 0 {
+				return nil, fmt.Errorf("line %v: found a list item without a key", i+1)
+			}
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			values[prefix] = append(values[prefix], unquoteConfigValue(item))
+			continue
+		}
+
+		idx := strings.Index(trimmed, ":")
+		if idx < 1 || (idx < len(trimmed)-1 && trimmed[idx+1] != ' ') {
+			return nil, fmt.Errorf("line %v: expected a key-value pair but found: `%v`", i+1, trimmed)
+		}
+
+		key := joinConfigKey(prefix, strings.Trim(trimmed[:idx], `"'`))
+		value := strings.TrimSpace(trimmed[idx+1:])
+
+		if len(value) == 0 {
+			stack = append(stack, level{indent, key})
+		} else {
+			values[key] = parseConfigValue(value)
+		}
+	}
+
+	return values, nil
+}
+
+/****************************** Config utilities ****************************/
+
+func joinConfigKey(prefix, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Parses a scalar value or an inline list of the form: [a, "b", c]
+func parseConfigValue(val string) []string {
+	val = strings.TrimSpace(stripConfigComment(val))
+
+	if strings.HasPrefix(val, "[") && strings.HasSuffix(val, "]") {
+		items := []string{}
+		for _, v := range strings.Split(val[1:len(val)-1], ",") {
+			if v = strings.TrimSpace(v); len(v) > 0 {
+				items = append(items, unquoteConfigValue(v))
+			}
+		}
+		return items
+	}
+
+	return []string{unquoteConfigValue(val)}
+}
+
+func unquoteConfigValue(val string) string {
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		return val[1 : len(val)-1]
+	}
+	return val
+}
+
+// Removes trailing comments that are not enclosed in quotes
+func stripConfigComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Returns the section of the configuration file corresponding to a command, i.e. `remote.add` for the `app remote add` command
+func (c *Command) configSection() string {
+	names := []string{}
+	for cmd := c; cmd != nil && cmd.parent != nil; cmd = cmd.parent {
+		names = append([]string{cmd.name}, names...)
+	}
+	return strings.Join(names, ".")
+}
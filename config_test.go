@@ -0,0 +1,128 @@
+package gommander
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigParsers(t *testing.T) {
+	expected := configValues{
+		"token":         {"abc"},
+		"deploy.region": {"eu-west"},
+		"deploy.tags":   {"web", "api"},
+		"deploy.port":   {"8080"},
+	}
+
+	{
+		values, err := parseJSONConfig([]byte(`{"token": "abc", "deploy": {"region": "eu-west", "tags": ["web", "api"], "port": 8080}}`))
+		assertEq(t, err, nil, "JSON config parsing failed")
+		assertDeepEq(t, values, expected, "JSON config parsed incorrectly")
+	}
+
+	{
+		values, err := parseINIConfig([]byte(`
+# root options
+token = "abc"
+
+[deploy]
+region = eu-west # inline comment
+tags = ["web", "api"]
+port = 8080
+`))
+		assertEq(t, err, nil, "INI config parsing failed")
+		assertDeepEq(t, values, expected, "INI config parsed incorrectly")
+	}
+
+	{
+		values, err := parseYAMLConfig([]byte(`
+token: 'abc'
+deploy:
+  region: eu-west
+  tags:
+    - web
+    - "api"
+  port: 8080 # inline comment
+`))
+		assertEq(t, err, nil, "YAML config parsing failed")
+		assertDeepEq(t, values, expected, "YAML config parsed incorrectly")
+	}
+
+	{
+		_, err := parseYAMLConfig([]byte("token abc"))
+		assertNe(t, err, nil, "Invalid YAML config did not return an error")
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	_ = os.WriteFile(path, []byte("deploy:\n  region: eu\n  tag: [one, two]\n"), 0644)
+
+	newApp := func() *Command {
+		app := App().Name("app").EnvPrefix("GOMMANDER_CFG").ConfigFile("gommander-test")
+		app.SubCommand("deploy").
+			AddOption(NewOption("region").Argument("<region>")).
+			AddOption(NewOption("tag").Argument("<tag>")).
+			AddOption(NewOption("zone").Required(true).AddArgument(NewArgument("<zone>").Default("a")))
+		return app
+	}
+
+	{
+		parser := NewParser(newApp())
+		matches, err := parser.parse([]string{"--config", path, "deploy"})
+		assert(t, err == nil, "Parsing with a config file failed")
+
+		region, _ := matches.GetOptionValue("region")
+		regionSrc, _ := matches.GetValueSource("region")
+		zoneSrc, _ := matches.GetValueSource("zone")
+
+		assertEq(t, region, "eu", "Option value not read from config file")
+		assertEq(t, regionSrc, SourceConfig, "Config value source recorded incorrectly")
+		assertEq(t, zoneSrc, SourceDefault, "Default value source recorded incorrectly")
+		assertDeepEq(t, matches.GetAllOptionInstances("tag"), []string{"one", "two"}, "Config list values parsed incorrectly")
+	}
+
+	os.Setenv("GOMMANDER_CFG_REGION", "us")
+	defer os.Unsetenv("GOMMANDER_CFG_REGION")
+
+	{
+		parser := NewParser(newApp())
+		matches, _ := parser.parse([]string{"--config", path, "deploy"})
+
+		region, _ := matches.GetOptionValue("region")
+		source, _ := matches.GetValueSource("region")
+
+		assertEq(t, region, "us", "Config file took precedence over env vars")
+		assertEq(t, source, SourceEnv, "Env value source recorded incorrectly")
+	}
+
+	{
+		parser := NewParser(newApp())
+		matches, _ := parser.parse([]string{"--config", path, "deploy", "--region", "af"})
+
+		region, _ := matches.GetOptionValue("region")
+		source, _ := matches.GetValueSource("region")
+
+		assertEq(t, region, "af", "Config file took precedence over the command line")
+		assertEq(t, source, SourceCommandLine, "Command line value source recorded incorrectly")
+	}
+}
+
+func TestConfigDefaultLocation(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(dir, "gommander-test"), 0755)
+	_ = os.WriteFile(filepath.Join(dir, "gommander-test", "config.json"), []byte(`{"deploy": {"region": "eu"}}`), 0644)
+
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	app := App().Name("app").ConfigFile("gommander-test")
+	app.SubCommand("deploy").Option("--region <region>", "The region to deploy to")
+
+	parser := NewParser(app)
+	matches, _ := parser.parse([]string{"deploy"})
+	region, _ := matches.GetOptionValue("region")
+
+	assertEq(t, region, "eu", "Config file not loaded from the XDG config directory")
+}
@@ -0,0 +1,400 @@
+package gommander
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The format in which reference documentation is generated
+type DocFormat string
+
+const (
+	Markdown DocFormat = "markdown"
+	HTML     DocFormat = "html"
+)
+
+type docCell struct {
+	text string
+	code bool
+	link string
+}
+
+type docTable struct {
+	title   string
+	headers []string
+	rows    [][]docCell
+}
+
+type docPage struct {
+	cmd        *Command
+	title      string
+	anchor     string
+	help       string
+	usage      string
+	aliases    []string
+	parent     docCell
+	tables     []docTable
+	discussion string
+}
+
+// Writes reference documentation for the command and all its visible subcommands as a single page, in which each command has its own linked section.
+// The documentation is generated from the same definitions used for the help output: the usage, arguments, flags, options, aliases, subcommand groups and discussion of each command
+func (c *Command) GenerateDocs(format DocFormat, w io.Writer) error {
+	if err := validateDocFormat(format); err != nil {
+		return err
+	}
+
+	pages := []docPage{}
+	c.walkVisible(func(cmd *Command) {
+		pages = append(pages, cmd.docPage(func(target *Command) string {
+			return "#" + target.docAnchor()
+		}))
+	})
+
+	var doc strings.Builder
+	if format == Markdown {
+		for _, p := range pages {
+			p.markdown(&doc, 2)
+		}
+	} else {
+		doc.WriteString(htmlHeader(pages[0].title))
+		for _, p := range pages {
+			p.html(&doc, 2)
+		}
+		doc.WriteString(htmlFooter)
+	}
+
+	_, err := io.WriteString(w, doc.String())
+	return err
+}
+
+// Writes reference documentation for the command and all its visible subcommands into a directory tree with one page per command. The page of each command is written into a directory named after the path of the command,
+// i.e. `dir/index.md` for the root command and `dir/remote/add/index.md` for the `remote add` subcommand. Pages are linked to the pages of their parent and subcommands
+func (c *Command) GenerateDocsTree(format DocFormat, dir string) error {
+	if err := validateDocFormat(format); err != nil {
+		return err
+	}
+
+	ext := ".md"
+	if format == HTML {
+		ext = ".html"
+	}
+
+	depth := len(c.commandPath())
+	pageDir := func(cmd *Command) string {
+		return filepath.Join(append([]string{dir}, cmd.commandPath()[depth:]...)...)
+	}
+
+	var err error
+	c.walkVisible(func(cmd *Command) {
+		if err != nil {
+			return
+		}
+
+		page := cmd.docPage(func(target *Command) string {
+			if target == cmd.parent {
+				return path.Join("..", "index"+ext)
+			}
+			return path.Join(target.name, "index"+ext)
+		})
+
+		var doc strings.Builder
+		if format == Markdown {
+			page.markdown(&doc, 1)
+		} else {
+			doc.WriteString(htmlHeader(page.title))
+			page.html(&doc, 1)
+			doc.WriteString(htmlFooter)
+		}
+
+		if err = os.MkdirAll(pageDir(cmd), 0o755); err == nil {
+			err = os.WriteFile(filepath.Join(pageDir(cmd), "index"+ext), []byte(doc.String()), 0o644)
+		}
+	})
+
+	return err
+}
+
+func validateDocFormat(format DocFormat) error {
+	if format != Markdown && format != HTML {
+		return fmt.Errorf("unsupported documentation format: `%v`, expected one of: `[%v, %v]`", format, Markdown, HTML)
+	}
+	return nil
+}
+
+// Invokes the callback for the command and all its visible descendants, parents first
+func (c *Command) walkVisible(cb func(*Command)) {
+	cb(c)
+	for _, s := range c.visibleSubCommands() {
+		s.walkVisible(cb)
+	}
+}
+
+func (c *Command) docAnchor() string {
+	return strings.Join(c.commandPath(), "-")
+}
+
+// Collects the contents of the documentation page of a command. The link function returns the link to the page or section of another command
+func (c *Command) docPage(link func(*Command) string) docPage {
+	app := c._getAppRef()
+	page := docPage{
+		cmd:        c,
+		title:      strings.Join(c.commandPath(), " "),
+		anchor:     c.docAnchor(),
+		help:       c.help,
+		usage:      strings.TrimSpace(c._getUsageStr()),
+		aliases:    c.aliases,
+		discussion: strings.TrimSpace(dedent(c.discussion)),
+	}
+
+	if len(c.customUsageStr) == 0 {
+		usage := []string{page.usage}
+		if len(c.allFlags()) > 0 {
+			usage = append(usage, app.flagsHelpValue)
+		}
+		if len(c.allOptions()) > 0 {
+			usage = append(usage, app.optionsHelpValue)
+		}
+		if len(c.arguments) > 0 {
+			usage = append(usage, app.argsHelpValue)
+		}
+		if len(c.visibleSubCommands()) > 0 {
+			usage = append(usage, app.subCmdsHelpValue)
+		}
+		page.usage = strings.Join(usage, " ")
+	}
+
+	if c.parent != nil {
+		page.parent = docCell{text: strings.Join(c.parent.commandPath(), " "), link: link(c.parent)}
+	}
+
+	if len(c.arguments) > 0 {
+		table := docTable{title: app.argsHelpHeading, headers: []string{"Argument", "Type", "Default", "Description"}}
+		for _, a := range c.arguments {
+			table.rows = append(table.rows, []docCell{
+				{text: a.getRawValue(), code: true},
+				{text: string(a.ArgType)},
+				{text: a.DefaultValue, code: a.hasDefaultValue()},
+				{text: docDescription(a.HelpStr, a.EnvVar)},
+			})
+		}
+		page.tables = append(page.tables, table)
+	}
+
+	if len(c.allFlags()) > 0 {
+		table := docTable{title: app.flagsHelpHeading, headers: []string{"Flag", "Description"}}
+		for _, f := range c.allFlags() {
+			leading, _ := f.generate(app)
+			table.rows = append(table.rows, []docCell{
+				{text: strings.TrimSpace(leading), code: true},
+				{text: f.HelpStr},
+			})
+		}
+		page.tables = append(page.tables, table)
+	}
+
+	if len(c.allOptions()) > 0 {
+		table := docTable{title: app.optionsHelpHeading, headers: []string{"Option", "Type", "Default", "Description"}}
+		for _, o := range c.allOptions() {
+			leading, _ := o.generate(app)
+			row := []docCell{{text: strings.TrimSpace(leading), code: true}, {}, {}, {text: docDescription(o.HelpStr, o.getEnvVar(app))}}
+			if o.Arg != nil {
+				row[1] = docCell{text: string(o.Arg.ArgType)}
+				row[2] = docCell{text: o.Arg.DefaultValue, code: o.Arg.hasDefaultValue()}
+			}
+			table.rows = append(table.rows, row)
+		}
+		page.tables = append(page.tables, table)
+	}
+
+	if subCmds := c.visibleSubCommands(); len(subCmds) > 0 {
+		cmdTable := func(title string, cmds []*Command) docTable {
+			table := docTable{title: title, headers: []string{"Command", "Description"}}
+			for _, s := range cmds {
+				table.rows = append(table.rows, []docCell{
+					{text: s.name, code: true, link: link(s)},
+					{text: s.help},
+				})
+			}
+			return table
+		}
+
+		if len(c.subCmdGroups) == 0 {
+			page.tables = append(page.tables, cmdTable(app.subCmdsHelpHeading, subCmds))
+		} else {
+			groups := []string{}
+			for g := range c.subCmdGroups {
+				groups = append(groups, g)
+			}
+			sort.Strings(groups)
+
+			grouped := []*Command{}
+			for _, g := range groups {
+				members := []*Command{}
+				for _, s := range c.subCmdGroups[g] {
+					if !s.hidden {
+						members = append(members, s)
+					}
+				}
+				grouped = append(grouped, members...)
+				page.tables = append(page.tables, cmdTable(g, members))
+			}
+
+			others := []*Command{}
+			for _, s := range subCmds {
+				if !sliceContains(grouped, s) {
+					others = append(others, s)
+				}
+			}
+			if len(others) > 0 {
+				page.tables = append(page.tables, cmdTable("Other Commands", others))
+			}
+		}
+	}
+
+	return page
+}
+
+func docDescription(help, env string) string {
+	if len(env) > 0 {
+		return fmt.Sprintf("%v (env: `%v`)", help, env)
+	}
+	return help
+}
+
+/****************************** Markdown rendering ****************************/
+
+func (p *docPage) markdown(doc *strings.Builder, level int) {
+	heading := func(lvl int, val string) {
+		doc.WriteString(fmt.Sprintf("%v %v\n\n", strings.Repeat("#", lvl), val))
+	}
+
+	heading(level, p.title)
+	if len(p.help) > 0 {
+		doc.WriteString(p.help + "\n\n")
+	}
+	if len(p.parent.text) > 0 {
+		doc.WriteString(fmt.Sprintf("Parent command: %v\n\n", p.parent.markdown()))
+	}
+
+	heading(level+1, "Usage")
+	doc.WriteString(fmt.Sprintf("```\n%v\n```\n\n", p.usage))
+
+	if len(p.aliases) > 0 {
+		doc.WriteString(fmt.Sprintf("**Aliases:** `%v`\n\n", strings.Join(p.aliases, "`, `")))
+	}
+
+	for _, t := range p.tables {
+		heading(level+1, docTitle(t.title))
+		doc.WriteString("| " + strings.Join(t.headers, " | ") + " |\n")
+		doc.WriteString("|" + strings.Repeat(" --- |", len(t.headers)) + "\n")
+		for _, row := range t.rows {
+			cells := []string{}
+			for _, cell := range row {
+				cells = append(cells, cell.markdown())
+			}
+			doc.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		doc.WriteString("\n")
+	}
+
+	if len(p.discussion) > 0 {
+		heading(level+1, "Discussion")
+		doc.WriteString(p.discussion + "\n\n")
+	}
+}
+
+func (cell docCell) markdown() string {
+	text := strings.ReplaceAll(cell.text, "|", `\|`)
+	if cell.code && len(text) > 0 {
+		text = "`" + text + "`"
+	}
+	if len(cell.link) > 0 {
+		text = fmt.Sprintf("[%v](%v)", text, cell.link)
+	}
+	return text
+}
+
+/****************************** HTML rendering ****************************/
+
+const htmlFooter = "</body>\n</html>\n"
+
+func htmlHeader(title string) string {
+	return fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%v</title>\n</head>\n<body>\n", html.EscapeString(title))
+}
+
+func (p *docPage) html(doc *strings.Builder, level int) {
+	heading := func(lvl int, val string) {
+		doc.WriteString(fmt.Sprintf("<h%v>%v</h%v>\n", lvl, html.EscapeString(val), lvl))
+	}
+
+	doc.WriteString(fmt.Sprintf("<section id=\"%v\">\n", html.EscapeString(p.anchor)))
+	heading(level, p.title)
+	if len(p.help) > 0 {
+		doc.WriteString(fmt.Sprintf("<p>%v</p>\n", html.EscapeString(p.help)))
+	}
+	if len(p.parent.text) > 0 {
+		doc.WriteString(fmt.Sprintf("<p>Parent command: %v</p>\n", p.parent.html()))
+	}
+
+	heading(level+1, "Usage")
+	doc.WriteString(fmt.Sprintf("<pre><code>%v</code></pre>\n", html.EscapeString(p.usage)))
+
+	if len(p.aliases) > 0 {
+		aliases := []string{}
+		for _, a := range p.aliases {
+			aliases = append(aliases, docCell{text: a, code: true}.html())
+		}
+		doc.WriteString(fmt.Sprintf("<p><strong>Aliases:</strong> %v</p>\n", strings.Join(aliases, ", ")))
+	}
+
+	for _, t := range p.tables {
+		heading(level+1, docTitle(t.title))
+		doc.WriteString("<table>\n<thead>\n<tr>")
+		for _, h := range t.headers {
+			doc.WriteString(fmt.Sprintf("<th>%v</th>", html.EscapeString(h)))
+		}
+		doc.WriteString("</tr>\n</thead>\n<tbody>\n")
+		for _, row := range t.rows {
+			doc.WriteString("<tr>")
+			for _, cell := range row {
+				doc.WriteString(fmt.Sprintf("<td>%v</td>", cell.html()))
+			}
+			doc.WriteString("</tr>\n")
+		}
+		doc.WriteString("</tbody>\n</table>\n")
+	}
+
+	if len(p.discussion) > 0 {
+		heading(level+1, "Discussion")
+		for _, para := range strings.Split(p.discussion, "\n\n") {
+			doc.WriteString(fmt.Sprintf("<p>%v</p>\n", html.EscapeString(strings.TrimSpace(para))))
+		}
+	}
+	doc.WriteString("</section>\n")
+}
+
+func (cell docCell) html() string {
+	text := html.EscapeString(cell.text)
+	if cell.code && len(text) > 0 {
+		text = "<code>" + text + "</code>"
+	}
+	if len(cell.link) > 0 {
+		text = fmt.Sprintf("<a href=\"%v\">%v</a>", html.EscapeString(cell.link), text)
+	}
+	return text
+}
+
+// Converts the upper-case help headings into title case, i.e. `SUBCOMMANDS` into `Subcommands`
+func docTitle(val string) string {
+	if len(val) == 0 || strings.ToUpper(val) != val {
+		return val
+	}
+	return val[:1] + strings.ToLower(val[1:])
+}
@@ -0,0 +1,74 @@
+package gommander
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocsGolden(t *testing.T) {
+	app := App().Name("git").Version("2.0.0").Help("A distributed version control system")
+
+	remote := NewCommand("remote").Alias("r").Help("Manage tracked repositories")
+	remote.SubCommand("add").
+		Help("Add a new remote").
+		Argument("<name>", "The name of the remote").
+		AddArgument(NewArgument("[url]").Help("The url | address of the remote").Default("origin").Env("GIT_URL")).
+		Option("-t --track <branch>", "Track only the given branch").
+		Discussion(`
+		Adds a remote named <name> for the repository at <url>.
+
+		The command git fetch <name> can then be used.
+		`)
+
+	app.AddSubCommand(remote)
+	app.SubCommand("commit").Help("Record changes to the repository")
+	app.SubCommand("gc").Help("Cleanup unnecessary files").Hidden(true)
+	app.SubCommandGroup("Collaboration", []*Command{remote})
+	app._init()
+
+	for ext, format := range map[string]DocFormat{"md": Markdown, "html": HTML} {
+		var doc bytes.Buffer
+		if err := app.GenerateDocs(format, &doc); err != nil {
+			t.Fatal(err)
+		}
+		_assertGolden(t, filepath.Join("testdata", "docs", "single."+ext+".golden"), doc.String())
+	}
+
+	var buf bytes.Buffer
+	err := app.GenerateDocs(DocFormat("pdf"), &buf)
+	assert(t, err != nil, "Unsupported doc format accepted")
+}
+
+func TestDocsTree(t *testing.T) {
+	dir := t.TempDir()
+	app := App().Name("git").Help("A distributed version control system")
+	app.SubCommand("remote").Help("Manage tracked repositories").SubCommand("add").Help("Add a new remote")
+	app.SubCommand("commit").Help("Record changes to the repository")
+	app.SubCommand("gc").Help("Cleanup unnecessary files").Hidden(true)
+	app._init()
+
+	err := app.GenerateDocsTree(Markdown, dir)
+	assert(t, err == nil, "Failed to generate docs tree: ", err)
+
+	for _, p := range []string{"index.md", "remote/index.md", "remote/add/index.md", "commit/index.md"} {
+		_, err := os.Stat(filepath.Join(dir, p))
+		assert(t, err == nil, "Docs page not generated: ", p)
+	}
+	_, err = os.Stat(filepath.Join(dir, "gc"))
+	assert(t, err != nil, "Docs generated for hidden subcommand")
+
+	root, _ := os.ReadFile(filepath.Join(dir, "index.md"))
+	assert(t, strings.Contains(string(root), "[`remote`](remote/index.md)"), "Subcommand not linked in docs tree")
+
+	add, _ := os.ReadFile(filepath.Join(dir, "remote", "add", "index.md"))
+	assert(t, strings.HasPrefix(string(add), "# git remote add\n"), "Wrong title for subcommand page")
+	assert(t, strings.Contains(string(add), "Parent command: [git remote](../index.md)"), "Parent not linked in docs tree")
+
+	err = app.GenerateDocsTree(HTML, dir)
+	assert(t, err == nil, "Failed to generate html docs tree: ", err)
+	_, err = os.Stat(filepath.Join(dir, "remote", "add", "index.html"))
+	assert(t, err == nil, "Html docs page not generated")
+}
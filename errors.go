@@ -58,21 +58,19 @@ func generateError(cmd *Command, e Event, args []string) Error {
 				{
 					msg = fmt.Sprintf("found unknown flag or option: `%v`", args[0])
 					ctx = fmt.Sprintf("The value: `%v`, could not be resolved as a flag or option.", args[0])
+					ctx += cmd.switchHint(args[0])
 				}
 			case 2:
 				{
 					msg = fmt.Sprintf("failed to resolve option: %v in value: %v", args[0], args[1])
-					ctx = fmt.Sprintf("Found value: %v, with long option syntax but the option: %v is not valid in this context", args[1], args[0])
+					ctx = fmt.Sprintf("Found value: %v, with long option syntax but the option: %v is not valid in this context.", args[1], args[0])
+					ctx += cmd.switchHint(args[0])
 				}
 			case 3:
 				{
-					msg = fmt.Sprintf("unknown shorthand flag: `%v` in: `%v`", args[0], args[1])
-					ctx = fmt.Sprintf("Expected to find valid flags values in: `%v`, but instead found: `-%v` , which could not be resolved as a flag", args[1], args[0])
-				}
-			default:
-				{
-					msg = "syntax not supported"
-					ctx = "Passing option arguments using the `=` sign is only supported when using long options i.e. --port=8000 is valid but -p=8000 is not"
+					msg = fmt.Sprintf("unknown shorthand flag or option: `%v` in: `%v`", args[0], args[1])
+					ctx = fmt.Sprintf("Expected to find valid flags or options in: `%v`, but the character: `%v` at position %v could not be resolved as a flag or option.", args[1], args[0], args[2])
+					ctx += cmd.switchHint("-" + args[0])
 				}
 			}
 
@@ -83,28 +81,72 @@ func generateError(cmd *Command, e Event, args []string) Error {
 			msg = fmt.Sprintf("failed to resolve argument: `%v`", args[0])
 			ctx = fmt.Sprintf("Found value: `%v`, which was unexpected or is invalid in this context", args[0])
 		}
-	case UnknownCommand:
+	case InvalidDefinition:
 		{
-			code = 40
-			msg = fmt.Sprintf("no such subcommand found: `%v`", args[0])
-			suggestions := cmd.suggestSubCmd(args[0])
+			code = 1
+			msg = args[0]
 
 			var context strings.Builder
-			context.WriteString(fmt.Sprintf("The value: `%v`, could not be resolved as a subcommand. ", args[0]))
-			if len(suggestions) > 0 {
-				context.WriteString("Did you mean ")
-
-				for i, s := range suggestions {
-					if i > 0 {
-						context.WriteString("or ")
-					}
-					context.WriteString(fmt.Sprintf("`%v` ", s))
+			context.WriteString(fmt.Sprintf("Found %v error(s) in the definition of the program: ", len(args)))
+			for i, a := range args {
+				if i > 0 {
+					context.WriteString("; ")
 				}
-
-				context.WriteString("?")
+				context.WriteString(a)
 			}
 			ctx = context.String()
 		}
+	case ActionFailed:
+		{
+			code = 1
+			msg = args[0]
+			ctx = fmt.Sprintf("An error was encountered when running the command: `%v`. %v", cmd.name, args[0])
+		}
+	case InvalidConfig:
+		{
+			code = 70
+			msg = fmt.Sprintf("failed to load config file: `%v`", args[0])
+			ctx = fmt.Sprintf("An error was encountered when reading the config file: `%v`. %v", args[0], args[1])
+		}
+	case InvalidArgumentCount:
+		{
+			code = 80
+			msg = fmt.Sprintf("wrong number of values for argument: `%v`", args[0])
+			ctx = fmt.Sprintf("Expected %v value(s) for argument: `%v`, but found %v", args[2], args[0], args[1])
+		}
+	case AmbiguousPrefix:
+		{
+			code = 90
+			msg = fmt.Sprintf("ambiguous value: `%v`", args[0])
+			ctx = fmt.Sprintf("The value: `%v`, is a prefix of more than one of: `[%v]`. Use a longer prefix or the full name to select one of them", args[0], strings.Join(args[1:], ", "))
+		}
+	case ConflictingOptions:
+		{
+			code = 100
+			msg = fmt.Sprintf("conflicting options: `%v` and `%v`", args[0], args[1])
+			ctx = fmt.Sprintf("The option: `%v` cannot be used together with: `%v`. Pass only one of them", args[0], args[1])
+		}
+	case MissingDependentOption:
+		{
+			code = 110
+			msg = fmt.Sprintf("missing option: `%v` required by: `%v`", args[0], args[1])
+			ctx = fmt.Sprintf("The option: `%v` is required when: `%v` is passed, but no value was provided", args[0], args[1])
+		}
+	case MissingOneOfRequired:
+		{
+			code = 120
+			msg = fmt.Sprintf("expected one of: `[%v]`", strings.Join(args, ", "))
+			ctx = fmt.Sprintf("Exactly one of the options: `[%v]` is required, but none of them was provided", strings.Join(args, ", "))
+		}
+	case UnknownCommand:
+		{
+			code = 40
+			msg = fmt.Sprintf("no such subcommand found: `%v`", args[0])
+			ctx = fmt.Sprintf("The value: `%v`, could not be resolved as a subcommand.", args[0])
+			if suggestions := cmd.suggestSubCmd(args[0]); len(suggestions) > 0 {
+				ctx += " " + didYouMean(suggestions)
+			}
+		}
 
 	}
 
@@ -121,6 +163,21 @@ func (e *Error) ErrorMsg() string {
 	return e.message
 }
 
+// Implements the error interface, returning the error message
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Returns the event that caused the error
+func (e *Error) GetKind() Event {
+	return e.kind
+}
+
+// Returns the exit code the program would exit with for the error
+func (e *Error) GetExitCode() int {
+	return e.exitCode
+}
+
 func (e *Error) GetErrorString(c *Command) string {
 	fmter := e._writeError(c)
 	return fmter.GetString()
@@ -1,7 +1,6 @@
 package gommander
 
 import (
-	"os"
 	"sort"
 )
 
@@ -25,6 +24,22 @@ const (
 	InvalidArgumentValue
 	// An event emitted when a required option is not provided. Single argument: the name of the missing option
 	MissingRequiredOption
+	// Emitted before parsing when the command tree contains definition errors such as duplicate flags or invalid default values. The messages of all the definition errors are passed along as arguments
+	InvalidDefinition
+	// Emitted when the action of a command, or one of its lifecycle hooks, returns an error. Single argument: the message of the returned error
+	ActionFailed
+	// Emitted when the configuration file cannot be read or parsed. Two arguments are passed along: the path of the file and the error encountered
+	InvalidConfig
+	// Emitted when the number of values passed to a variadic argument is outside the bounds set via the `AtLeast()` and `AtMost()` methods. Three arguments are passed along: the argument, the number of values found and the violated bound, i.e. `at least 2`
+	InvalidArgumentCount
+	// Emitted when prefix matching is enabled and a value is the prefix of more than one subcommand or long option. The value is passed along, followed by the names of the matching candidates
+	AmbiguousPrefix
+	// Emitted when more than one member of an exclusive or one-of-required option group is passed. Two arguments are passed along: the first two members found
+	ConflictingOptions
+	// Emitted when a member of a required-together option group is passed without the others, or when the condition of a conditional requirement is met without its member. Two arguments are passed along: the missing member and the member or condition that requires it, i.e. `--mode=remote`
+	MissingDependentOption
+	// Emitted when none of the members of a one-of-required option group is passed. The members of the group are passed along as arguments
+	MissingOneOfRequired
 )
 
 var eventsSlice = []Event{
@@ -32,7 +47,11 @@ var eventsSlice = []Event{
 	OutputHelp, OutputVersion,
 	UnknownCommand, UnknownOption,
 	UnresolvedArgument, InvalidArgumentValue,
-	MissingRequiredOption,
+	MissingRequiredOption, InvalidDefinition,
+	ActionFailed, InvalidConfig,
+	InvalidArgumentCount, AmbiguousPrefix,
+	ConflictingOptions, MissingDependentOption,
+	MissingOneOfRequired,
 }
 
 type EventListener struct {
@@ -95,10 +114,6 @@ func (em *EventEmitter) emit(cfg EventConfig) {
 			for _, lstnr := range v {
 				lstnr.cb(&cfg)
 			}
-
-			if !isTestMode() {
-				os.Exit(cfg.exitCode)
-			}
 		}
 	}
 }
@@ -24,8 +24,9 @@ func main() {
 			Variadic(true),
 	)
 
-	// An alternate, less verbose option syntax. Duplicate args will be ignored.
-	app.Argument("<text...>", "The text to echo out")
+	// An alternate, less verbose syntax would be:
+	// app.Argument("<text...>", "The text to echo out")
+	// Declaring the same argument twice is reported as an error when the program is parsed.
 
 	// Creating flags
 	app.AddFlag(
@@ -35,8 +36,8 @@ func main() {
 			Help("Whether or not to add a newline"),
 	)
 
-	// An alternate way for creating flags. Duplicate values also ignored
-	app.Flag("-n --newline", "Whether or not to add a newline")
+	// An alternate way for creating flags would be:
+	// app.Flag("-n --newline", "Whether or not to add a newline")
 
 	// Declaring a callback for the command
 	app.Action(echoCb)
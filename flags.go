@@ -5,11 +5,12 @@ import (
 )
 
 type Flag struct {
-	Name     string
-	LongVal  string
-	ShortVal string
-	HelpStr  string
-	IsGlobal bool
+	Name        string
+	LongVal     string
+	ShortVal    string
+	HelpStr     string
+	IsGlobal    bool
+	IsNegatable bool
 }
 
 // A Builder method for creating a new flag. It sets the name of the flag and the long version of the flag by appending `--` to the name then returns the flag for further manipulation.
@@ -32,12 +33,18 @@ func (f *Flag) Help(val string) *Flag {
 	return f
 }
 
-// A method for setting a flag as global. Global flags are propagated to all the subcommands of a given command
+// A method for setting a flag as global. Global flags apply to all the descendants of the command on which they are defined, and can be passed before or after the names of subcommands
 func (f *Flag) Global(val bool) *Flag {
 	f.IsGlobal = val
 	return f
 }
 
+// A method for setting a flag as negatable. Negatable flags can be turned off explicitly by prefixing their long value with `no-`, i.e. `--no-color`, and are displayed as `--[no-]color` in help output. A flag can also be declared negatable by passing `--[no-]color` to the `.Flag()` method
+func (f *Flag) Negatable(val bool) *Flag {
+	f.IsNegatable = val
+	return f
+}
+
 func helpFlag() *Flag {
 	return &Flag{
 		Name:     "help",
@@ -61,7 +68,10 @@ func newFlag(val string, help string) Flag {
 	values := strings.Split(val, " ")
 
 	for _, v := range values {
-		if strings.HasPrefix(v, "--") {
+		if strings.HasPrefix(v, "--[no-]") {
+			flag.LongVal = "--" + strings.TrimPrefix(v, "--[no-]")
+			flag.IsNegatable = true
+		} else if strings.HasPrefix(v, "--") {
 			flag.LongVal = v
 		} else if strings.HasPrefix(v, "-") {
 			flag.ShortVal = v
@@ -81,8 +91,35 @@ func (f *Flag) generate(app *Command) (string, string) {
 	}
 
 	if len(f.LongVal) > 0 {
-		leading.WriteString(" " + f.LongVal)
+		leading.WriteString(" " + f.displayLongVal())
 	}
 
 	return leading.String(), f.HelpStr
 }
+
+// Returns the long value of the flag as displayed in help output, i.e. `--[no-]color` for negatable flags
+func (f *Flag) displayLongVal() string {
+	if f.IsNegatable && len(f.LongVal) > 0 {
+		return "--[no-]" + strings.TrimPrefix(f.LongVal, "--")
+	}
+	return f.LongVal
+}
+
+// Returns the value used to turn off a negatable flag, i.e. `--no-color`, or an empty string if the flag is not negatable
+func (f *Flag) negatedLongVal() string {
+	if f.IsNegatable && len(f.LongVal) > 0 {
+		return "--no-" + strings.TrimPrefix(f.LongVal, "--")
+	}
+	return ""
+}
+
+// Returns every value the flag can be passed as, including the negated long value of negatable flags
+func (f *Flag) switches() []string {
+	switches := []string{}
+	for _, v := range []string{f.ShortVal, f.LongVal, f.negatedLongVal()} {
+		if len(v) > 0 {
+			switches = append(switches, v)
+		}
+	}
+	return switches
+}
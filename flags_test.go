@@ -29,6 +29,20 @@ func TestFlagsCreation(t *testing.T) {
 	}
 }
 
+func TestNegatableFlags(t *testing.T) {
+	flag := NewFlag("color").Short('c').Help("Colorize the output").Negatable(true)
+	flagB := newFlag("-c --[no-]color", "Colorize the output")
+
+	assertDeepEq(t, *flag, flagB, "Negatable flag creation functions are out of sync")
+	assertEq(t, flag.negatedLongVal(), "--no-color", "Negated long value derived incorrectly")
+
+	gotL, _ := flag.generate(App())
+	assertEq(t, gotL, "-c, --[no-]color", "Negatable flags not displayed as negatable")
+
+	app := App().Flag("--[no-]color", "Colorize the output").Flag("--no-color", "Conflicting flag")
+	assertEq(t, len(app.getDefinitionErrors()), 1, "Conflict with a negated value not reported")
+}
+
 func BenchmarkFlagBuilder(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		NewFlag("version").Short('V').Help("A version flag")
@@ -10,25 +10,26 @@ import (
 	"strings"
 )
 
-var cache = make(map[string]bool, 0)
-
-func clearCache() {
-	for k := range cache {
-		delete(cache, k)
-	}
-}
-
 type CommandCallback = func(*ParserMatches)
 
+// A command callback that returns an error. Errors returned are displayed in the same way as parser errors and cause the program to exit with a code of 1, or the code returned by the error's `GetExitCode()` method if it has one
+type CommandCallbackE = func(*ParserMatches) error
+
 type Command struct {
 	aliases            []string
 	arguments          []*Argument
 	author             string
-	callback           CommandCallback
+	callback           CommandCallbackE
+	preRun             CommandCallbackE
+	postRun            CommandCallbackE
+	persistentPreRun   CommandCallbackE
+	persistentPostRun  CommandCallbackE
 	discussion         string
 	emitter            EventEmitter
 	flags              []*Flag
 	help               string
+	hidden             bool
+	isBuiltin          bool
 	isRoot             bool
 	name               string
 	options            []*Option
@@ -40,7 +41,17 @@ type Command struct {
 	version            string
 	usageStr           string
 	customUsageStr     string
+	definitionErrs     []error
+	exitFn             func(int)
+	suggestionDistance int
+	maxSuggestions     int
+	envPrefix          string
+	configName         string
+	bindings           []fieldBinding
+	executing          bool
+	initialized        bool
 	subCmdGroups       map[string][]*Command
+	optionGroups       []*optionGroup
 	appRef             *Command
 	subCmdsHelpHeading string
 	subCmdsHelpValue   string
@@ -50,6 +61,7 @@ type Command struct {
 	optionsHelpValue   string
 	argsHelpHeading    string
 	argsHelpValue      string
+	groupsHelpHeading  string
 }
 
 func App() *Command {
@@ -79,6 +91,9 @@ func NewCommand(name string) *Command {
 		optionsHelpValue:   "[OPTION]",
 		argsHelpHeading:    "ARGS",
 		argsHelpValue:      "[ARG]",
+		groupsHelpHeading:  "OPTION GROUPS",
+		suggestionDistance: defaultSuggestionDistance,
+		maxSuggestions:     defaultMaxSuggestions,
 	}
 }
 
@@ -93,8 +108,8 @@ func (c *Command) GetAuthor() string { return c.author }
 // Returns a slice of the configured arguments for a command
 func (c *Command) GetArguments() []*Argument { return c.arguments }
 
-// Returns a slice of the configured flags
-func (c *Command) GetFlags() []*Flag { return c.flags }
+// Returns a slice of the configured flags, including the global flags inherited from the ancestors of the command
+func (c *Command) GetFlags() []*Flag { return c.allFlags() }
 
 // Returns the help string / description that gets printed out on help
 func (c *Command) GetHelp() string { return c.help }
@@ -102,8 +117,8 @@ func (c *Command) GetHelp() string { return c.help }
 // Returns the configured name of a command
 func (c *Command) GetName() string { return c.name }
 
-// Returns the slice of options belonging to a command
-func (c *Command) GetOptions() []*Option { return c.options }
+// Returns the slice of options belonging to a command, including the global options inherited from the ancestors of the command
+func (c *Command) GetOptions() []*Option { return c.allOptions() }
 
 // Returns the parent of a command or nil if none is found
 func (c *Command) GetParent() *Command { return c.parent }
@@ -126,27 +141,64 @@ func (c *Command) GetUsageStr() string {
 
 // This method set the callback to be excuted when a command is matched
 func (c *Command) Action(cb CommandCallback) *Command {
+	c.callback = func(pm *ParserMatches) error {
+		cb(pm)
+		return nil
+	}
+	return c
+}
+
+// Identical to the `.Action()` method except the callback returns an error, which gets handled the same way as parser errors
+func (c *Command) ActionE(cb CommandCallbackE) *Command {
 	c.callback = cb
 	return c
 }
 
+// Sets a hook to be executed before the action of the command. If the hook returns an error, the action is not invoked
+func (c *Command) PreRun(cb CommandCallbackE) *Command {
+	c.preRun = cb
+	return c
+}
+
+// Sets a hook to be executed after the action of the command, if the action succeeds
+func (c *Command) PostRun(cb CommandCallbackE) *Command {
+	c.postRun = cb
+	return c
+}
+
+// Sets a hook to be executed before the action of the command and of all its descendants. Persistent hooks of parent commands are executed first
+func (c *Command) PersistentPreRun(cb CommandCallbackE) *Command {
+	c.persistentPreRun = cb
+	return c
+}
+
+// Sets a hook to be executed after the action of the command and of all its descendants. Persistent hooks of parent commands are executed last
+func (c *Command) PersistentPostRun(cb CommandCallbackE) *Command {
+	c.persistentPostRun = cb
+	return c
+}
+
 // A method for adding a flag to a command. It is similar to the `.Flag()` method except this method receives an instance of an already created flag while `.Flag()` receives a string, creates a flag from it and call this method internally
 func (c *Command) AddFlag(flag *Flag) *Command {
-	id := fmt.Sprintf("flag-%s", flag.Name)
-	if !cache[id] {
-		cache[id] = true
-		c.flags = append(c.flags, flag)
+	if err := c.checkConflicts("flag", flag.ShortVal, flag.LongVal); err != nil {
+		c.definitionErrs = append(c.definitionErrs, err)
+		return c
+	}
+	if err := c.checkConflicts("flag", "", flag.negatedLongVal()); err != nil {
+		c.definitionErrs = append(c.definitionErrs, err)
+		return c
 	}
+	c.flags = append(c.flags, flag)
 	return c
 }
 
 // A method for adding a new option to a command. The `.Option()` method invokes this one internally. Identical to the `.AddFlag()` method except this one is for options instead of flags
 func (c *Command) AddOption(opt *Option) *Command {
-	id := fmt.Sprintf("option-%s", opt.Name)
-	if !cache[id] {
-		cache[id] = true
-		c.options = append(c.options, opt)
+	if err := c.checkConflicts("option", opt.ShortVal, opt.LongVal); err != nil {
+		c.definitionErrs = append(c.definitionErrs, err)
+		return c
 	}
+	c.options = append(c.options, opt)
 	return c
 }
 
@@ -156,12 +208,16 @@ func (c *Command) Alias(alias string) *Command {
 	return c
 }
 
+// A method for adding an argument to a command. It is invoked internally by the `.Argument()` method. Arguments with duplicate names are not added and are reported as definition errors when the program is parsed
 func (c *Command) AddArgument(arg *Argument) *Command {
-	id := fmt.Sprintf("arg-%s", arg.Name)
-	if !cache[id] {
-		cache[id] = true
-		c.arguments = append(c.arguments, arg)
+	for _, a := range c.arguments {
+		if a.Name == arg.Name {
+			err := newDefinitionError(DuplicateDefinition, "duplicate argument: `%v` is already defined on command: `%v`", arg.getRawValue(), c.name)
+			c.definitionErrs = append(c.definitionErrs, err)
+			return c
+		}
 	}
+	c.arguments = append(c.arguments, arg)
 	return c
 }
 
@@ -192,12 +248,24 @@ func (c *Command) Discussion(info string) *Command {
 	return c
 }
 
+// Marks the command as one added by the package itself, such as the help subcommand
+func (c *Command) builtin() *Command {
+	c.isBuiltin = true
+	return c
+}
+
 // Simply sets the help string, otherwise known as description of a command
 func (c *Command) Help(help string) *Command {
 	c.help = help
 	return c
 }
 
+// Sets whether a command is hidden. Hidden commands can still be invoked but are not printed out in help or shell completions
+func (c *Command) Hidden(val bool) *Command {
+	c.hidden = val
+	return c
+}
+
 // Sets the name of a command, and updates the usage str as well
 func (c *Command) Name(name string) *Command {
 	c.name = name
@@ -249,6 +317,11 @@ func (c *Command) ArgsHelpHeading(val string) *Command {
 	return c
 }
 
+func (c *Command) OptionGroupsHelpHeading(val string) *Command {
+	c.groupsHelpHeading = val
+	return c
+}
+
 func (c *Command) SubCmdsHelpValue(val string) *Command {
 	c.subCmdsHelpValue = val
 	return c
@@ -279,35 +352,43 @@ func (c *Command) AddToGroup(name string) *Command {
 
 // Receives a reference to a command, sets the command parent and usage string then adds its to the slice of subcommands. This method is called internally by the `.SubCommand()` method but users can also invoke it directly
 func (c *Command) AddSubCommand(subCmd *Command) *Command {
-	id := fmt.Sprintf("subcmd-%s", subCmd.name)
-	if !cache[id] {
-		cache[id] = true
-		subCmd.parent = c
-		c.subCommands = append(c.subCommands, subCmd)
-
-		cmdPath := []string{c.usageStr, subCmd.usageStr}
-		subCmd.usageStr = strings.Join(cmdPath, " ")
-
-		// Propagate global flags to children
-		for _, f := range c.GetFlags() {
-			if f.IsGlobal {
-				subCmd.AddFlag(f)
-			}
+	for _, v := range append([]string{subCmd.name}, subCmd.aliases...) {
+		if _, err := c.findSubcommand(v); err == nil {
+			err := newDefinitionError(DuplicateDefinition, "duplicate subcommand: `%v` is already defined on command: `%v`", v, c.name)
+			c.definitionErrs = append(c.definitionErrs, err)
+			return c
 		}
+	}
 
-		// propagate theme
-		subCmd.theme = c.theme
+	subCmd.parent = c
+	c.subCommands = append(c.subCommands, subCmd)
 
-		if c.isRoot {
-			subCmd.appRef = c
-		} else {
-			subCmd.appRef = c.appRef
-		}
+	cmdPath := []string{c.usageStr, subCmd.usageStr}
+	subCmd.usageStr = strings.Join(cmdPath, " ")
+
+	// propagate theme
+	subCmd.theme = c.theme
+
+	if c.isRoot {
+		subCmd.appRef = c
+	} else {
+		subCmd.appRef = c.appRef
 	}
+	subCmd.syncSubCommands()
 
 	return c
 }
 
+// Updates the usage strings, themes and app references of subcommands that were attached to the command before it was attached to its own parent
+func (c *Command) syncSubCommands() {
+	for _, s := range c.subCommands {
+		s.usageStr = strings.Join([]string{c.usageStr, s.name}, " ")
+		s.theme = c.theme
+		s.appRef = c.appRef
+		s.syncSubCommands()
+	}
+}
+
 // An easier method for creating sub_cmds while avoiding too much function paramets nesting. It accepts the name of the new sub_cmd and returns the newly created sub_cmd
 func (c *Command) SubCommand(name string) *Command {
 	subCmd := NewCommand(name)
@@ -327,7 +408,8 @@ func (c *Command) _init() {
 		c.removeFlag("--version")
 	}
 
-	if c.settings[IncludeHelpSubcommand] && len(c.subCommands) > 0 {
+	_, helpErr := c.findSubcommand("help")
+	if c.settings[IncludeHelpSubcommand] && len(c.subCommands) > 0 && helpErr != nil {
 		validSubcmds := []string{}
 
 		for _, c := range c.subCommands {
@@ -335,6 +417,7 @@ func (c *Command) _init() {
 		}
 
 		c.SubCommand("help").
+			builtin().
 			Help("Print out help information for the passed command").
 			AddArgument(
 				NewArgument("<COMMAND>").
@@ -352,6 +435,20 @@ func (c *Command) _init() {
 			})
 	}
 
+	if _, err := c.findSubcommand("completion"); c.settings[IncludeCompletionSubcommand] && err != nil {
+		c.AddSubCommand(completionSubCmd())
+	}
+
+	if _, err := c.findSubcommand("man"); c.settings[IncludeManSubcommand] && err != nil {
+		c.AddSubCommand(manSubCmd())
+	}
+
+	// the program may be parsed more than once, i.e. by repeated calls to `.ExecuteFrom()`, so listeners are only registered the first time
+	if c.initialized {
+		return
+	}
+	c.initialized = true
+
 	// Default help listener cannot be overridden
 	c.emitter.on(OutputHelp, func(ec *EventConfig) {
 		cmd := ec.matchedCmd
@@ -414,34 +511,42 @@ func (c *Command) _isExpectingValues() bool {
 	return len(c.subCommands) > 0 || (len(c.arguments) > 0 && !hasDefaults(c.arguments))
 }
 
-func (c *Command) _parse(vals []string) {
+func (c *Command) _parse(vals []string) *Error {
 	// TODO: Init/build the commands- set default listeners, add help subcmd, sync settings
 	c._init()
 	c._setBinName(vals[0])
 
+	if errs := c.Validate(); len(errs) > 0 {
+		args := []string{}
+		for _, e := range errs {
+			args = append(args, e.Error())
+		}
+		err := generateError(c, InvalidDefinition, args)
+		c.emitError(&err, c)
+		return &err
+	}
+
 	rawArgs := vals[1:]
+	// the entry point is always handled since the dynamic completion scripts call it regardless of the settings of the program
+	if len(rawArgs) > 0 && rawArgs[0] == completeCmdName {
+		c.complete(rawArgs[1:], os.Stdout)
+		return nil
+	}
+
 	parser := NewParser(c)
 	matches, err := parser.parse(rawArgs)
 
 	if err != nil {
-		event := EventConfig{
-			err:        *err,
-			args:       err.args,
-			event:      err.kind,
-			exitCode:   err.exitCode,
-			appRef:     c,
-			matchedCmd: matches.matchedCmd,
-		}
-		c.emit(event)
+		c.emitError(err, matches.matchedCmd)
+		return err
 	}
 
-	// TODO: No errors, check special flags
 	matchedCmd := matches.GetMatchedCommand()
 	cmdIdx := matches.GetMatchedCommandIndex()
 
 	// Check special flags
 	// TODO: Sync with program settings
-	if matches.ContainsFlag("help") {
+	if matches.helpRequested() {
 		event := EventConfig{
 			event:      OutputHelp,
 			exitCode:   0,
@@ -449,6 +554,7 @@ func (c *Command) _parse(vals []string) {
 			matchedCmd: matchedCmd,
 		}
 		c.emit(event)
+		return nil
 	} else if matches.ContainsFlag("version") {
 		event := EventConfig{
 			event:      OutputVersion,
@@ -457,6 +563,12 @@ func (c *Command) _parse(vals []string) {
 			matchedCmd: matchedCmd,
 		}
 		c.emit(event)
+		return nil
+	}
+
+	if err := matchedCmd.fillBindings(matches); err != nil {
+		c.emitError(err, matchedCmd)
+		return err
 	}
 
 	showHelp := func() {
@@ -472,13 +584,51 @@ func (c *Command) _parse(vals []string) {
 		}
 		if (len(rawArgs) == 0 || len(matches.rawArgs[cmdIdx:]) == 0) && matchedCmd._isExpectingValues() {
 			showHelp()
-			return
+			return nil
+		}
+		// Invoke callback along with the lifecycle hooks
+		if e := matchedCmd.run(matches); e != nil {
+			err := generateError(matchedCmd, ActionFailed, []string{e.Error()})
+			if coder, ok := e.(interface{ GetExitCode() int }); ok {
+				err.exitCode = coder.GetExitCode()
+			}
+
+			c.emitError(&err, matchedCmd)
+			return &err
 		}
-		// Invoke callback
-		matchedCmd.callback(matches)
 	} else {
 		showHelp()
 	}
+
+	return nil
+}
+
+// Invokes the persistent pre-run hooks from the root command downwards, the pre-run hook, the action, the post-run hook and lastly the persistent post-run hooks from the command upwards. Execution stops at the first error
+func (c *Command) run(pm *ParserMatches) error {
+	lineage := []*Command{}
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		lineage = append([]*Command{cmd}, lineage...)
+	}
+
+	hooks := []CommandCallbackE{}
+	for _, cmd := range lineage {
+		hooks = append(hooks, cmd.persistentPreRun)
+	}
+	hooks = append(hooks, c.preRun, c.callback, c.postRun)
+	for i := len(lineage) - 1; i >= 0; i-- {
+		hooks = append(hooks, lineage[i].persistentPostRun)
+	}
+
+	for _, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+		if err := hook(pm); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // A method for parsing the arguments passed to a program and invoking the callback on a command if one is found. This method also handles any errors encountered while parsing.
@@ -486,15 +636,72 @@ func (c *Command) Parse() {
 	c._parse(os.Args)
 }
 
+// Identical to the `.Parse()` method but parses the provided values instead of os.Args. The first value is expected to be the name of the binary
 func (c *Command) ParseFrom(args []string) {
 	c._parse(args)
 }
 
+// Similar to the `.Parse()` method but the program never exits. Event listeners are still invoked, and any error encountered is returned as an `*Error` from which the intended exit code can be acquired
+func (c *Command) Execute() error {
+	return c.ExecuteFrom(os.Args)
+}
+
+// Identical to the `.Execute()` method but parses the provided values instead of os.Args
+func (c *Command) ExecuteFrom(args []string) error {
+	c.executing = true
+	defer func() { c.executing = false }()
+
+	if err := c._parse(args); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Sets a prefix used to derive environment variables for every option of the program, i.e. with a prefix of `APP`, the value of the `--log-level` option is read from `APP_LOG_LEVEL` when the option is not passed. Only the prefix of the root command is used
+func (c *Command) EnvPrefix(val string) *Command {
+	c.envPrefix = val
+	return c
+}
+
+// Sets the function invoked when the program exits after an event, `os.Exit` by default. Only the exit function of the root command is used
+func (c *Command) ExitFunc(fn func(int)) *Command {
+	c.exitFn = fn
+	return c
+}
+
+func (c *Command) exit(code int) {
+	app := c._getAppRef()
+	if app == nil {
+		app = c
+	}
+
+	if app.executing {
+		return
+	} else if app.exitFn != nil {
+		app.exitFn(code)
+	} else if !isTestMode() {
+		os.Exit(code)
+	}
+}
+
 /****************************** Event emitter functionality ****************************/
 
-// Makes a call to the Command event emitter to `emit` a new event from the passed config
+// Makes a call to the Command event emitter to `emit` a new event from the passed config then exits the program with the event exit code
 func (c *Command) emit(cfg EventConfig) {
 	c.emitter.emit(cfg)
+	c.exit(cfg.exitCode)
+}
+
+func (c *Command) emitError(err *Error, matchedCmd *Command) {
+	event := EventConfig{
+		err:        *err,
+		args:       err.args,
+		event:      err.kind,
+		exitCode:   err.exitCode,
+		appRef:     c,
+		matchedCmd: matchedCmd,
+	}
+	c.emit(event)
 }
 
 // Used to add a new listener for a specific event which gets triggered when the event occurs
@@ -534,6 +741,16 @@ func (c *Command) hasSubcommands() bool {
 	return len(c.subCommands) > 0
 }
 
+func (c *Command) visibleSubCommands() []*Command {
+	visible := []*Command{}
+	for _, sc := range c.subCommands {
+		if !sc.hidden {
+			visible = append(visible, sc)
+		}
+	}
+	return visible
+}
+
 func (c *Command) findSubcommand(val string) (*Command, error) {
 	for _, sc := range c.subCommands {
 		includes := func(val string) bool {
@@ -553,40 +770,121 @@ func (c *Command) findSubcommand(val string) (*Command, error) {
 	return NewCommand(""), errors.New("no such subcmd")
 }
 
-func (c *Command) suggestSubCmd(val string) []string {
-	var minMatchSize = 3
-	var matches []string
+func (c *Command) findOption(val string) (*Option, error) {
+	for _, o := range c.allOptions() {
+		if o.ShortVal == val || o.LongVal == val {
+			return o, nil
+		}
+	}
 
-	cmdMap := make(map[string]int, 0)
+	return NewOption(""), errors.New("no such option")
+}
 
-	for _, v := range c.subCommands {
-		cmdMap[v.name] = 0
-	}
+// Returns the flags that apply to the command: its own flags followed by the global flags inherited from its ancestors
+func (c *Command) allFlags() []*Flag {
+	flags, _ := c.inheritedSwitches()
+	return append(append([]*Flag{}, c.flags...), flags...)
+}
 
-	for _, sc := range c.subCommands {
-		for i, v := range strings.Split(val, "") {
-			if len(sc.name) > i {
-				var next string
-				current := string(sc.name[i])
+// Returns the options that apply to the command: its own options followed by the global options inherited from its ancestors
+func (c *Command) allOptions() []*Option {
+	_, opts := c.inheritedSwitches()
+	return append(append([]*Option{}, c.options...), opts...)
+}
 
-				if len(sc.name) > i+1 {
-					next = string(sc.name[i+1])
-				}
+// Collects the global flags and options of the ancestors of the command, closest ancestor first. Global flags and options are resolved whenever they are needed rather than copied to subcommands, so they apply regardless of the order in which the command tree is built.
+// Values already used by the command, or by a closer ancestor, shadow those further up the tree. Such conflicts are reported by the `.Validate()` method
+func (c *Command) inheritedSwitches() ([]*Flag, []*Option) {
+	flags, opts := []*Flag{}, []*Option{}
+	taken := map[string]bool{}
+	take := func(vals ...string) bool {
+		for _, v := range vals {
+			if len(v) > 0 && taken[v] {
+				return false
+			}
+		}
+		for _, v := range vals {
+			if len(v) > 0 {
+				taken[v] = true
+			}
+		}
+		return true
+	}
 
-				if next == v || current == v {
-					cmdMap[sc.name] += 1
-				}
+	for _, f := range c.flags {
+		take(f.ShortVal, f.LongVal)
+	}
+	for _, o := range c.options {
+		take(o.ShortVal, o.LongVal)
+	}
+
+	for p := c.parent; p != nil; p = p.parent {
+		for _, f := range p.flags {
+			if f.IsGlobal && take(f.ShortVal, f.LongVal) {
+				flags = append(flags, f)
+			}
+		}
+		for _, o := range p.options {
+			if o.IsGlobal && take(o.ShortVal, o.LongVal) {
+				opts = append(opts, o)
 			}
 		}
 	}
+	return flags, opts
+}
 
-	for k, v := range cmdMap {
-		if v >= minMatchSize {
-			matches = append(matches, k)
+// Reports whether a flag or option with the provided name, short or long value is defined on the command itself, leaving out inherited ones
+func (c *Command) definesSwitch(val string) bool {
+	for _, f := range c.flags {
+		if f.Name == val || f.ShortVal == val || f.LongVal == val {
+			return true
 		}
 	}
+	for _, o := range c.options {
+		if o.Name == val || o.ShortVal == val || o.LongVal == val {
+			return true
+		}
+	}
+	return false
+}
 
-	return matches
+// Reports whether a global flag or option with the provided name, short or long value is defined on the command itself
+func (c *Command) definesGlobalSwitch(val string) bool {
+	for _, f := range c.flags {
+		if f.IsGlobal && (f.Name == val || f.ShortVal == val || f.LongVal == val) {
+			return true
+		}
+	}
+	for _, o := range c.options {
+		if o.IsGlobal && (o.Name == val || o.ShortVal == val || o.LongVal == val) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the command on which a flag applying to the command was defined, either the command itself or the ancestor the flag is inherited from
+func (c *Command) flagOwner(flag *Flag) *Command {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for _, f := range cmd.flags {
+			if f == flag {
+				return cmd
+			}
+		}
+	}
+	return c
+}
+
+// Returns the command on which an option applying to the command was defined, either the command itself or the ancestor the option is inherited from
+func (c *Command) optionOwner(opt *Option) *Command {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for _, o := range cmd.options {
+			if o == opt {
+				return cmd
+			}
+		}
+	}
+	return c
 }
 
 func (c *Command) removeFlag(val string) {
@@ -602,8 +900,16 @@ func (c *Command) removeFlag(val string) {
 func (c *Command) _getAppRef() *Command {
 	if c.isRoot {
 		return c
+	} else if c.appRef != nil {
+		return c.appRef
 	}
-	return c.appRef
+
+	// subcommands attached to their parent before the parent was attached to the app hold no reference to it
+	cmd := c
+	for !cmd.isRoot && cmd.parent != nil {
+		cmd = cmd.parent
+	}
+	return cmd
 }
 
 func (c *Command) _getUsageStr() string {
@@ -625,6 +931,15 @@ func (c *Command) _getUsageStr() string {
 	return newUsage.String()
 }
 
+// Returns the names of the command and its ancestors, starting from the root command, i.e. `[app remote add]`
+func (c *Command) commandPath() []string {
+	path := []string{}
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		path = append([]string{cmd.name}, path...)
+	}
+	return path
+}
+
 func (c *Command) _setBinName(val string) {
 	if len(c.name) == 0 {
 		binName := filepath.Base(val)
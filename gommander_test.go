@@ -1,6 +1,8 @@
 package gommander
 
 import (
+	"errors"
+	"strconv"
 	"testing"
 )
 
@@ -19,7 +21,6 @@ func TestCommandMetadata(t *testing.T) {
 }
 
 func TestCommandSettings(t *testing.T) {
-	clearCache()
 	app := App()
 	app.SubCommand("dummy").Argument("<uint:count>", "a count arg")
 
@@ -56,7 +57,6 @@ func TestEventListeners(t *testing.T) {
 func TestRootCmdArgs(t *testing.T) {
 	// Test single required arg
 	{
-		clearCache()
 		app := App()
 
 		app.Argument("<file>", "file to open").
@@ -70,7 +70,6 @@ func TestRootCmdArgs(t *testing.T) {
 
 	// Test required arg error
 	{
-		clearCache()
 		app := App()
 		app.Argument("<file>", "file to open")
 
@@ -84,7 +83,6 @@ func TestRootCmdArgs(t *testing.T) {
 
 	// Test optional args parsing
 	{
-		clearCache()
 		app := App()
 		app.Argument("[file]", "file to open").
 			Action(func(pm *ParserMatches) {
@@ -190,3 +188,163 @@ func BenchmarkBuildWithArgs(b *testing.B) {
 
 	_compareVariants(b, constructor, buidler, composite)
 }
+
+func TestPerCommandRegistration(t *testing.T) {
+	app := App()
+	app.SubCommand("push").Option("-n --name <value>", "Name to push").Argument("<file>", "File to push")
+	app.SubCommand("pull").Option("-n --name <value>", "Name to pull").Argument("<file>", "File to pull")
+
+	for _, sc := range app.GetSubCommands() {
+		assertEq(t, len(sc.GetOptions()), 1, "Option dropped from subcommand: ", sc.GetName())
+		assertEq(t, len(sc.GetArguments()), 1, "Argument dropped from subcommand: ", sc.GetName())
+	}
+	assertEq(t, len(app.getDefinitionErrors()), 0, "Options on separate commands reported as duplicates")
+
+	// duplicates on the same command are reported
+	app.SubCommand("push")
+	push, _ := app.findSubcommand("push")
+	push.Flag("--name", "Conflicts with the name option").
+		Argument("[file]", "Duplicate argument")
+
+	assertEq(t, len(app.GetSubCommands()), 2, "Duplicate subcommand added")
+	assertEq(t, len(push.GetFlags()), 1, "Conflicting flag added")
+	assertEq(t, len(app.getDefinitionErrors()), 3, "Duplicate definitions not reported")
+}
+
+func TestConcurrentParsing(t *testing.T) {
+	done := make(chan bool)
+
+	for i := 0; i < 8; i++ {
+		go func(i int) {
+			app := App()
+			app.SubCommand("serve").Option("-p --port <int:port>", "The port to use")
+
+			parser := NewParser(app)
+			port := strconv.Itoa(8000 + i)
+			matches, err := parser.parse([]string{"serve", "--port", port})
+
+			assert(t, err == nil, "Concurrent parsing failed")
+			val, _ := matches.GetOptionValue("port")
+			assertEq(t, val, port, "Concurrent parsing mixed up values")
+			done <- true
+		}(i)
+	}
+
+	for i := 0; i < 8; i++ {
+		<-done
+	}
+}
+
+func TestExecute(t *testing.T) {
+	app := App().Set(OverrideAllDefaultListeners, true)
+	app.SubCommand("serve").Option("-p --port <int:port>", "The port to use").Action(func(pm *ParserMatches) {})
+
+	{
+		err := app.ExecuteFrom([]string{"my_bin", "serve", "--port", "8000"})
+		assertEq(t, err, nil, "Execute returned an error for valid args")
+	}
+
+	{
+		err := app.ExecuteFrom([]string{"my_bin", "serve", "--port", "eight"})
+		e, ok := err.(*Error)
+
+		assert(t, ok, "Execute did not return a typed error")
+		assertEq(t, e.GetKind(), InvalidArgumentValue, "Execute returned the wrong error kind")
+		assertEq(t, e.GetExitCode(), 10, "Execute returned the wrong exit code")
+	}
+
+	{
+		err := app.ExecuteFrom([]string{"my_bin", "serve", "--help"})
+		assertEq(t, err, nil, "Execute returned an error for the help flag")
+	}
+}
+
+func TestRepeatedExecution(t *testing.T) {
+	app := App().Name("app").Version("0.1.0").Author("vndaba").Help("A test program")
+
+	exec := func() {
+		app.ExecuteFrom([]string{"app", "--version"})
+		app.ExecuteFrom([]string{"app", "--version"})
+	}
+	assertStdOut(t, "app 0.1.0\nvndaba\nA test program\napp 0.1.0\nvndaba\nA test program\n", exec, "Listeners registered again on repeated executions")
+}
+
+func TestExitFunc(t *testing.T) {
+	codes := []int{}
+	app := App().
+		Set(OverrideAllDefaultListeners, true).
+		ExitFunc(func(code int) { codes = append(codes, code) })
+	app.SubCommand("serve").Action(func(pm *ParserMatches) {})
+
+	app.ParseFrom([]string{"my_bin", "unknown"})
+	app.ParseFrom([]string{"my_bin", "serve", "--help"})
+	app.ExecuteFrom([]string{"my_bin", "unknown"})
+
+	assertDeepEq(t, codes, []int{40, 0}, "Exit function invoked incorrectly")
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	app := App().Set(OverrideAllDefaultListeners, true)
+	app.Flag("-V --verbose", "Verbosity").Flag("-V --very", "Conflicting flag")
+	app.AddArgument(NewArgument("<lang>").ValidateWith([]string{"ENG", "FRE"}).Default("SPA"))
+
+	err := app.ExecuteFrom([]string{"my_bin", "ENG"})
+	e, ok := err.(*Error)
+
+	assert(t, ok, "Definition errors not returned as a typed error")
+	assertEq(t, e.GetKind(), InvalidDefinition, "Definition errors returned with the wrong kind")
+	assertEq(t, len(e.args), 2, "Not all definition errors were reported")
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	calls := []string{}
+	hook := func(name string) CommandCallbackE {
+		return func(pm *ParserMatches) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	app := App().
+		PersistentPreRun(hook("root-persistent-pre")).
+		PersistentPostRun(hook("root-persistent-post"))
+	remote := app.SubCommand("remote").
+		PersistentPreRun(hook("remote-persistent-pre")).
+		PersistentPostRun(hook("remote-persistent-post"))
+	remote.SubCommand("add").
+		PreRun(hook("pre")).
+		ActionE(hook("action")).
+		PostRun(hook("post"))
+
+	err := app.ExecuteFrom([]string{"my_bin", "remote", "add"})
+	expected := []string{
+		"root-persistent-pre", "remote-persistent-pre",
+		"pre", "action", "post",
+		"remote-persistent-post", "root-persistent-post",
+	}
+
+	assertEq(t, err, nil, "Lifecycle hooks returned an unexpected error")
+	assertDeepEq(t, calls, expected, "Lifecycle hooks executed in the wrong order")
+}
+
+func TestActionErrors(t *testing.T) {
+	called := false
+	app := App().Set(OverrideAllDefaultListeners, true)
+	app.SubCommand("login").
+		PersistentPreRun(func(pm *ParserMatches) error {
+			return errors.New("not authenticated")
+		}).
+		Action(func(pm *ParserMatches) { called = true })
+
+	app.On(ActionFailed, func(ec *EventConfig) {
+		assertEq(t, ec.GetArgs()[0], "not authenticated", "Action error message passed incorrectly")
+	})
+
+	err := app.ExecuteFrom([]string{"my_bin", "login"})
+	e, ok := err.(*Error)
+
+	assert(t, ok, "Action errors not returned as a typed error")
+	assert(t, !called, "Action invoked after a failing hook")
+	assertEq(t, e.GetKind(), ActionFailed, "Action error returned with the wrong kind")
+	assertEq(t, e.GetExitCode(), 1, "Action error returned with the wrong exit code")
+}
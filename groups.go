@@ -0,0 +1,202 @@
+package gommander
+
+import (
+	"fmt"
+	"strings"
+)
+
+type optionGroupKind byte
+
+const (
+	exclusiveGroup optionGroupKind = iota
+	requiredTogetherGroup
+	oneOfRequiredGroup
+	conditionalGroup
+)
+
+var optionGroupKinds = map[optionGroupKind]string{
+	exclusiveGroup:        "exclusive",
+	requiredTogetherGroup: "required_together",
+	oneOfRequiredGroup:    "one_of_required",
+	conditionalGroup:      "required_if",
+}
+
+func (k optionGroupKind) String() string {
+	return optionGroupKinds[k]
+}
+
+func optionGroupKindFromString(val string) (optionGroupKind, bool) {
+	for k, v := range optionGroupKinds {
+		if v == val {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// A constraint on the flags and options of a command. Members are referenced by their names, or by their short or long values
+type optionGroup struct {
+	cmd     *Command
+	kind    optionGroupKind
+	members []string
+	// The option and value that make the members of a conditional group required, i.e. `--mode` and `remote`
+	condOption string
+	condValue  string
+}
+
+// Declares a group of flags and options of which at most one can be passed at a time, i.e. `--json` and `--table`. Violations are reported through the `ConflictingOptions` event
+func (c *Command) ExclusiveGroup(members ...string) *Command {
+	c.optionGroups = append(c.optionGroups, &optionGroup{cmd: c, kind: exclusiveGroup, members: members})
+	return c
+}
+
+// Declares a group of flags and options that must be passed together, i.e. `--user` and `--password`. When any member of the group is passed, the missing ones are reported through the `MissingDependentOption` event
+func (c *Command) RequiredTogether(members ...string) *Command {
+	c.optionGroups = append(c.optionGroups, &optionGroup{cmd: c, kind: requiredTogetherGroup, members: members})
+	return c
+}
+
+// Declares a group of flags and options of which exactly one must be passed, i.e. `--file`, `--url` or `--stdin`. Passing none of them is reported through the `MissingOneOfRequired` event, while passing more than one is reported through the `ConflictingOptions` event
+func (c *Command) OneOfRequired(members ...string) *Command {
+	c.optionGroups = append(c.optionGroups, &optionGroup{cmd: c, kind: oneOfRequiredGroup, members: members})
+	return c
+}
+
+// Makes a flag or option required when another option is passed with the provided value, i.e. `--password` is required if `--mode=remote`. An empty value makes the member required whenever the option is passed at all. Violations are reported through the `MissingDependentOption` event
+func (c *Command) RequiredIf(member, option, value string) *Command {
+	c.optionGroups = append(c.optionGroups, &optionGroup{cmd: c, kind: conditionalGroup, members: []string{member}, condOption: option, condValue: value})
+	return c
+}
+
+// Resolves a group member to the long value of the matching flag or option, or its short value if it has none
+func (c *Command) resolveSwitch(val string) (string, bool) {
+	for _, f := range c.allFlags() {
+		if f.Name == val || f.ShortVal == val || f.LongVal == val {
+			return switchName(f.ShortVal, f.LongVal), true
+		}
+	}
+	for _, o := range c.allOptions() {
+		if o.Name == val || o.ShortVal == val || o.LongVal == val {
+			return switchName(o.ShortVal, o.LongVal), true
+		}
+	}
+	return "", false
+}
+
+// Returns the members of the group resolved to their switch values. Unknown members are reported by the `.Validate()` method and skipped here
+func (g *optionGroup) resolve() []string {
+	resolved := []string{}
+	for _, m := range g.members {
+		if val, ok := g.cmd.resolveSwitch(m); ok {
+			resolved = append(resolved, val)
+		}
+	}
+	return resolved
+}
+
+func (g *optionGroup) condition() string {
+	opt, _ := g.cmd.resolveSwitch(g.condOption)
+	if len(g.condValue) == 0 {
+		return opt
+	}
+	return fmt.Sprintf("%v=%v", opt, g.condValue)
+}
+
+func (g *optionGroup) validate() []DefinitionError {
+	errs := []DefinitionError{}
+	for _, m := range g.members {
+		if _, ok := g.cmd.resolveSwitch(m); !ok {
+			errs = append(errs, newDefinitionError(UnknownReference, "unknown flag or option: `%v` in an option group on command: `%v`", m, g.cmd.name))
+		}
+	}
+	if g.kind == conditionalGroup {
+		// conditions on a value can only refer to options
+		val, ok := g.cmd.resolveSwitch(g.condOption)
+		if _, err := g.cmd.findOption(val); !ok || (len(g.condValue) > 0 && err != nil) {
+			errs = append(errs, newDefinitionError(UnknownReference, "unknown option: `%v` in the condition of an option group on command: `%v`", g.condOption, g.cmd.name))
+		}
+	}
+	return errs
+}
+
+// Generates the leading and floating values of the group in help output
+func (g *optionGroup) generate(app *Command) (string, string) {
+	members := strings.Join(g.resolve(), ", ")
+	switch g.kind {
+	case exclusiveGroup:
+		return fmt.Sprintf("    %v ", members), "Mutually exclusive"
+	case requiredTogetherGroup:
+		return fmt.Sprintf("    %v ", members), "Required together"
+	case oneOfRequiredGroup:
+		return fmt.Sprintf("    %v ", members), "Exactly one is required"
+	default:
+		return fmt.Sprintf("    %v ", members), fmt.Sprintf("Required if %v", g.condition())
+	}
+}
+
+// Checks the flags and options passed to every command on the matched path against the option groups of that command, starting from the root. Options are considered passed unless their value came from a default value
+func (p *Parser) checkOptionGroups() *Error {
+	path := []*Command{}
+	for c := p.currentCmd; c != nil; c = c.parent {
+		path = append([]*Command{c}, path...)
+	}
+
+	groups := []*optionGroup{}
+	for _, c := range path {
+		groups = append(groups, c.optionGroups...)
+	}
+
+	for _, g := range groups {
+		// members are read from the matches of the command that declares the group
+		cmd := g.cmd
+		level := p.levelOf(cmd)
+		passed := func(val string) bool {
+			if level.GetFlagState(val) == FlagTrue {
+				return true
+			}
+			src, err := level.GetValueSource(val)
+			return err == nil && src != SourceDefault
+		}
+
+		members := g.resolve()
+		present := []string{}
+		for _, m := range members {
+			if passed(m) {
+				present = append(present, m)
+			}
+		}
+
+		switch g.kind {
+		case exclusiveGroup, oneOfRequiredGroup:
+			if len(present) > 1 {
+				err := generateError(cmd, ConflictingOptions, present[:2])
+				return &err
+			}
+			if g.kind == oneOfRequiredGroup && len(present) == 0 {
+				err := generateError(cmd, MissingOneOfRequired, members)
+				return &err
+			}
+		case requiredTogetherGroup:
+			if len(present) == 0 {
+				continue
+			}
+			for _, m := range members {
+				if !containsString(present, m) {
+					err := generateError(cmd, MissingDependentOption, []string{m, present[0]})
+					return &err
+				}
+			}
+		case conditionalGroup:
+			opt, ok := g.cmd.resolveSwitch(g.condOption)
+			if !ok || !passed(opt) || len(present) > 0 || len(members) == 0 {
+				continue
+			}
+			if len(g.condValue) == 0 || containsString(level.GetAllOptionInstances(opt), g.condValue) {
+				err := generateError(cmd, MissingDependentOption, []string{members[0], g.condition()})
+				return &err
+			}
+		}
+	}
+
+	return nil
+}
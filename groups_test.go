@@ -0,0 +1,164 @@
+package gommander
+
+import (
+	"testing"
+)
+
+func TestOptionGroups(t *testing.T) {
+	app := NewCommand("app").
+		Flag("--json", "Print as json").
+		Flag("--table", "Print as a table").
+		Flag("--stdin", "Read from stdin").
+		Option("--file <path>", "Read from a file").
+		Option("--url <url>", "Read from a url").
+		Option("--user <name>", "The user to log in as").
+		Option("--password <secret>", "The password of the user").
+		Option("--mode <mode>", "The mode to run in").
+		Option("--token <token>", "The token for remote access").
+		ExclusiveGroup("json", "table").
+		RequiredTogether("--user", "--password").
+		OneOfRequired("file", "url", "stdin").
+		RequiredIf("token", "mode", "remote")
+
+	parser := NewParser(app)
+	_, err := parser.parse([]string{"--stdin", "--json", "--user", "me", "--password", "pwd", "--mode", "remote", "--token", "abc"})
+	assert(t, err == nil, "Valid option groups reported: ", err)
+
+	parser = NewParser(app)
+	_, err = parser.parse([]string{"--file", "a.txt", "--mode", "local"})
+	assert(t, err == nil, "Conditional requirement reported for a different value: ", err)
+
+	_assertParserError(t, app,
+		[]string{"--stdin", "--json", "--table"},
+		[]string{"--json", "--table"},
+		ConflictingOptions,
+		"Exclusive group violation not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"--stdin", "--password", "pwd"},
+		[]string{"--user", "--password"},
+		MissingDependentOption,
+		"Required-together group violation not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"--json"},
+		[]string{"--file", "--url", "--stdin"},
+		MissingOneOfRequired,
+		"One-of-required group violation not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"--stdin", "--url", "x"},
+		[]string{"--url", "--stdin"},
+		ConflictingOptions,
+		"More than one member of a one-of-required group not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"--stdin", "--mode", "remote"},
+		[]string{"--token", "--mode=remote"},
+		MissingDependentOption,
+		"Conditional requirement violation not reported",
+	)
+
+	// help output
+	cases := []struct {
+		leading  string
+		floating string
+	}{
+		{"    --json, --table ", "Mutually exclusive"},
+		{"    --user, --password ", "Required together"},
+		{"    --file, --url, --stdin ", "Exactly one is required"},
+		{"    --token ", "Required if --mode=remote"},
+	}
+
+	for i, c := range cases {
+		gotL, gotF := app.optionGroups[i].generate(app)
+		assertEq(t, gotL, c.leading, "Option group members displayed incorrectly")
+		assertEq(t, gotF, c.floating, "Option group kind displayed incorrectly")
+	}
+}
+
+func TestOptionGroupsOnPath(t *testing.T) {
+	app := App().Name("app").
+		AddFlag(NewFlag("json").Help("Print as json").Global(true)).
+		AddFlag(NewFlag("table").Help("Print as a table").Global(true)).
+		Flag("--quiet", "Print nothing").
+		Flag("--verbose", "Print more output").
+		ExclusiveGroup("json", "table").
+		ExclusiveGroup("quiet", "verbose")
+	app.SubCommand("deploy").
+		Flag("--force", "Overwrite the deployment").
+		Flag("--dry-run", "Only print the changes").
+		ExclusiveGroup("force", "dry-run")
+
+	for _, args := range [][]string{{"deploy", "--json", "--table"}, {"--json", "deploy", "--table"}} {
+		_assertParserError(t, app,
+			args,
+			[]string{"--json", "--table"},
+			ConflictingOptions,
+			"Option group of global flags not checked on subcommands",
+		)
+	}
+
+	_assertParserError(t, app,
+		[]string{"--quiet", "--verbose", "deploy"},
+		[]string{"--quiet", "--verbose"},
+		ConflictingOptions,
+		"Option group of the root command not checked when a subcommand is matched",
+	)
+
+	_assertParserError(t, app,
+		[]string{"--quiet", "deploy", "--force", "--dry-run"},
+		[]string{"--force", "--dry-run"},
+		ConflictingOptions,
+		"Option group of the matched subcommand not checked",
+	)
+
+	parser := NewParser(app)
+	_, err := parser.parse([]string{"--quiet", "deploy", "--json", "--force"})
+	assert(t, err == nil, "Valid option groups on the matched path reported: ", err)
+}
+
+func TestOptionGroupsSpec(t *testing.T) {
+	previous := App().Name("app").
+		Flag("--json", "Print as json").
+		Flag("--table", "Print as a table").
+		Option("--mode <mode>", "The mode to run in").
+		Option("--token <token>", "The token for remote access").
+		ExclusiveGroup("json", "table")
+
+	spec := previous.ExportSpec()
+	assertDeepEq(t, AppFromSpec(spec).ExportSpec(), spec, "Option groups changed after a round trip")
+
+	app := App().Name("app").
+		Flag("--json", "Print as json").
+		Flag("--table", "Print as a table").
+		Option("--mode <mode>", "The mode to run in").
+		Option("--token <token>", "The token for remote access").
+		RequiredIf("token", "mode", "remote")
+
+	report := CompareSpecs(spec, app.ExportSpec())
+	assertDeepEq(t, _changeMessages(report.Breaking), []string{
+		"app: `required_if` option group `[token]` on `mode=remote` was added",
+	}, "Added option group not reported as breaking")
+	assertDeepEq(t, _changeMessages(report.Additions), []string{
+		"app: `exclusive` option group `[json, table]` was removed",
+	}, "Removed option group not reported as an addition")
+}
+
+func TestOptionGroupsValidation(t *testing.T) {
+	app := App().
+		Flag("--json", "Print as json").
+		Option("--mode <mode>", "The mode to run in").
+		ExclusiveGroup("json", "yaml").
+		RequiredIf("json", "verbose", "")
+
+	errs := app.Validate()
+	assertEq(t, len(errs), 2, "Unknown option group references not reported")
+	for _, e := range errs {
+		assertEq(t, e.Kind, UnknownReference, "Wrong kind for an unknown option group reference")
+	}
+}
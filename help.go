@@ -16,9 +16,10 @@ func (HelpWriter) Write(c *Command) {
 
 	hasArgs := len(c.arguments) > 0
 	hasDiscussion := len(c.discussion) > 0
-	hasFlags := len(c.flags) > 0
-	hasOptions := len(c.options) > 0
-	hasSubcmds := len(c.subCommands) > 0
+	hasFlags := len(c.allFlags()) > 0
+	hasOptions := len(c.allOptions()) > 0
+	subCmds := c.visibleSubCommands()
+	hasSubcmds := len(subCmds) > 0
 	hasCustomUsage := len(c.customUsageStr) > 0
 	hasSubcmdGroups := len(c.subCmdGroups) > 0
 
@@ -59,17 +60,22 @@ func (HelpWriter) Write(c *Command) {
 
 	if hasFlags {
 		fmter.section(app.flagsHelpHeading)
-		fmter.format(standardize(c.flags))
+		fmter.format(standardize(c.allFlags()))
 	}
 
 	if hasOptions {
 		fmter.section(app.optionsHelpHeading)
-		fmter.format(standardize(c.options))
+		fmter.format(standardize(c.allOptions()))
+	}
+
+	if len(c.optionGroups) > 0 {
+		fmter.section(app.groupsHelpHeading)
+		fmter.format(standardize(c.optionGroups))
 	}
 
 	if hasSubcmds && !hasSubcmdGroups {
 		fmter.section(app.subCmdsHelpHeading)
-		fmter.format(standardize(c.subCommands))
+		fmter.format(standardize(subCmds))
 	}
 
 	if hasSubcmds && hasSubcmdGroups {
@@ -92,7 +98,7 @@ func (HelpWriter) Write(c *Command) {
 		}
 
 		otherCmds := []*Command{}
-		for _, sc := range c.subCommands {
+		for _, sc := range subCmds {
 			if !groupContains(sc) {
 				otherCmds = append(otherCmds, sc)
 			}
@@ -123,7 +129,7 @@ func sliceContains(slice []*Command, val *Command) bool {
 }
 
 type FormatterType interface {
-	*Command | *Flag | *Option | *Argument
+	*Command | *Flag | *Option | *Argument | *optionGroup
 	FormatGenerator
 }
 
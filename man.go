@@ -0,0 +1,220 @@
+package gommander
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The section of the manual in which the generated man pages are placed
+const manSection = "1"
+
+var roffEscaper = strings.NewReplacer(`\`, `\e`, "-", `\-`)
+
+// Writes a roff man page for the command to the provided writer. The page contains the NAME, SYNOPSIS, DESCRIPTION, ARGUMENTS, OPTIONS and COMMANDS sections, followed by the command discussion, the author of the program and related pages.
+// The pages can be viewed using `man ./app.1`
+func (c *Command) GenerateManPage(w io.Writer) error {
+	app := c._getAppRef()
+	if len(app.name) == 0 {
+		return fmt.Errorf("cannot generate man pages for a program without a name, set one using the `.Name()` method")
+	}
+
+	var page strings.Builder
+	name := c.manPageName()
+	source := app.name
+	if len(app.version) > 0 {
+		source = fmt.Sprintf("%v %v", app.name, app.version)
+	}
+
+	page.WriteString(fmt.Sprintf(".TH %v %v \"\" %v \"User Commands\"\n", roffQuote(strings.ToUpper(name)), manSection, roffQuote(source)))
+
+	page.WriteString(".SH NAME\n")
+	if len(c.help) > 0 {
+		page.WriteString(fmt.Sprintf("%v \\- %v\n", roffEscape(name), roffEscape(c.help)))
+	} else {
+		page.WriteString(roffEscape(name) + "\n")
+	}
+
+	page.WriteString(".SH SYNOPSIS\n")
+	page.WriteString(fmt.Sprintf("\\fB%v\\fR", roffEscape(strings.TrimSpace(c._getUsageStr()))))
+	if len(c.customUsageStr) == 0 {
+		if len(c.allFlags()) > 0 {
+			page.WriteString(" " + roffEscape(app.flagsHelpValue))
+		}
+		if len(c.allOptions()) > 0 {
+			page.WriteString(" " + roffEscape(app.optionsHelpValue))
+		}
+		for _, a := range c.arguments {
+			page.WriteString(fmt.Sprintf(" \\fI%v\\fR", roffEscape(a.getRawValue())))
+		}
+		if len(c.visibleSubCommands()) > 0 {
+			page.WriteString(" " + roffEscape(app.subCmdsHelpValue))
+		}
+	}
+	page.WriteString("\n")
+
+	if len(c.help) > 0 {
+		page.WriteString(".SH DESCRIPTION\n")
+		page.WriteString(roffParagraphs(c.help))
+	}
+
+	if len(c.arguments) > 0 {
+		page.WriteString(".SH " + strings.ToUpper(app.argsHelpHeading) + "\n")
+		for _, a := range c.arguments {
+			_, help := a.generate(app)
+			page.WriteString(fmt.Sprintf(".TP\n\\fI%v\\fR\n%v\n", roffEscape(a.getRawValue()), roffEscape(help)))
+		}
+	}
+
+	if len(c.allFlags()) > 0 || len(c.allOptions()) > 0 {
+		page.WriteString(".SH OPTIONS\n")
+		for _, f := range c.allFlags() {
+			_, help := f.generate(app)
+			page.WriteString(fmt.Sprintf(".TP\n%v\n%v\n", roffSwitches(f.ShortVal, f.displayLongVal()), roffEscape(help)))
+		}
+		for _, o := range c.allOptions() {
+			switches := roffSwitches(o.ShortVal, o.LongVal)
+			if o.Arg != nil && o.IsValueOptional {
+				switches += fmt.Sprintf("[=\\fI%v\\fR]", roffEscape(o.displayArgs()))
+			} else if o.Arg != nil {
+				switches += fmt.Sprintf(" \\fI%v\\fR", roffEscape(o.displayArgs()))
+			}
+			_, help := o.generate(app)
+			page.WriteString(fmt.Sprintf(".TP\n%v\n%v\n", switches, roffEscape(help)))
+		}
+	}
+
+	if subCmds := c.visibleSubCommands(); len(subCmds) > 0 {
+		page.WriteString(".SH " + strings.ToUpper(app.subCmdsHelpHeading) + "\n")
+		for _, s := range subCmds {
+			page.WriteString(fmt.Sprintf(".TP\n\\fB%v\\fR\n%v\n", roffEscape(s.name), roffEscape(s.help)))
+		}
+	}
+
+	if len(c.discussion) > 0 {
+		page.WriteString(".SH DISCUSSION\n")
+		page.WriteString(roffParagraphs(dedent(c.discussion)))
+	}
+
+	if len(app.author) > 0 {
+		page.WriteString(".SH AUTHOR\n")
+		page.WriteString(roffEscape(app.author) + "\n")
+	}
+
+	related := []string{}
+	if c.parent != nil {
+		related = append(related, c.parent.manPageName())
+	}
+	for _, s := range c.visibleSubCommands() {
+		related = append(related, s.manPageName())
+	}
+	if len(related) > 0 {
+		page.WriteString(".SH SEE ALSO\n")
+		for i, r := range related {
+			sep := ","
+			if i == len(related)-1 {
+				sep = ""
+			}
+			page.WriteString(fmt.Sprintf(".BR %v (%v)%v\n", roffEscape(r), manSection, sep))
+		}
+	}
+
+	_, err := io.WriteString(w, page.String())
+	return err
+}
+
+// Writes man pages for the command and all of its visible subcommands into the provided directory, one page per command. Pages are named after the path of the command, i.e. `app-remote-add.1`.
+// It can be invoked from a `go generate` directive or via the hidden `man [dir]` subcommand enabled by the `IncludeManSubcommand` setting
+func (c *Command) GenerateManPages(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var generate func(cmd *Command) error
+	generate = func(cmd *Command) error {
+		file, err := os.Create(filepath.Join(dir, cmd.manPageName()+"."+manSection))
+		if err != nil {
+			return err
+		}
+		if err := cmd.GenerateManPage(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+
+		for _, s := range cmd.visibleSubCommands() {
+			if err := generate(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return generate(c)
+}
+
+func manSubCmd() *Command {
+	return NewCommand("man").
+		Help("Generate man pages for the program").
+		Hidden(true).
+		builtin().
+		AddArgument(
+			NewArgument("[dir]").
+				Help("The directory to write the man pages to").
+				Default("."),
+		).
+		Action(func(pm *ParserMatches) {
+			dir, _ := pm.GetArgValue("dir")
+			if err := pm.GetAppRef().GenerateManPages(dir); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		})
+}
+
+// Returns the name of the man page of a command, i.e. `app-remote-add`
+func (c *Command) manPageName() string {
+	return strings.Join(c.commandPath(), "-")
+}
+
+/****************************** Roff utilities ****************************/
+
+func roffEscape(val string) string {
+	lines := strings.Split(roffEscaper.Replace(val), "\n")
+	for i, l := range lines {
+		// lines beginning with control characters are interpreted as requests
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = `\&` + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func roffQuote(val string) string {
+	return `"` + strings.ReplaceAll(roffEscape(val), `"`, `\(dq`) + `"`
+}
+
+func roffSwitches(short, long string) string {
+	switches := []string{}
+	for _, s := range []string{short, long} {
+		if len(s) > 0 {
+			switches = append(switches, fmt.Sprintf("\\fB%v\\fR", roffEscape(s)))
+		}
+	}
+	return strings.Join(switches, ", ")
+}
+
+// Separates paragraphs in a block of text with the `.PP` request
+func roffParagraphs(val string) string {
+	var text strings.Builder
+	for i, p := range strings.Split(strings.TrimSpace(val), "\n\n") {
+		if i > 0 {
+			text.WriteString(".PP\n")
+		}
+		text.WriteString(roffEscape(strings.TrimSpace(p)) + "\n")
+	}
+	return text.String()
+}
@@ -0,0 +1,81 @@
+package gommander
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManPageGolden(t *testing.T) {
+	app := App().
+		Name("git").
+		Version("2.0.0").
+		Author("vndaba").
+		Help("A distributed version control system").
+		Discussion(`
+		Git is a fast, scalable, distributed revision control system.
+
+		See .gitconfig for configuration.
+		`)
+
+	remote := app.SubCommand("remote").Help("Manage tracked repositories")
+	remote.SubCommand("add").
+		Help("Add a new remote").
+		Argument("<name>", "The name of the remote").
+		Argument("<url>", "The url of the remote").
+		Option("-t --track <branch>", "Track only the given branch").
+		Flag("-f --fetch", "Fetch the remote branches")
+	remote.SubCommand("prune").Hidden(true)
+
+	app.Set(IncludeManSubcommand, true)
+	app._init()
+	add, _ := remote.findSubcommand("add")
+
+	for _, cmd := range []*Command{app, add} {
+		var page bytes.Buffer
+		if err := cmd.GenerateManPage(&page); err != nil {
+			t.Fatal(err)
+		}
+		_assertGolden(t, filepath.Join("testdata", "man", cmd.manPageName()+".1.golden"), page.String())
+	}
+}
+
+func TestGenerateManPages(t *testing.T) {
+	dir := t.TempDir()
+	app := App().Name("git").Set(IncludeManSubcommand, true)
+	remote := app.SubCommand("remote").Help("Manage tracked repositories")
+	remote.SubCommand("add").Help("Add a new remote")
+	remote.SubCommand("prune").Hidden(true)
+	app._init()
+
+	err := app.GenerateManPages(dir)
+	assert(t, err == nil, "Failed to generate man pages: ", err)
+
+	entries, _ := os.ReadDir(dir)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assertDeepEq(t, names, []string{"git-remote-add.1", "git-remote.1", "git.1"}, "Wrong man pages generated")
+
+	err = App().GenerateManPages(dir)
+	assert(t, err != nil && strings.Contains(err.Error(), "without a name"), "Man pages generated for a program without a name")
+}
+
+func TestManSubcommand(t *testing.T) {
+	dir := t.TempDir()
+	app := App().Name("git").Set(IncludeManSubcommand, true)
+	remote := app.SubCommand("remote").Help("Manage tracked repositories")
+	remote.SubCommand("add").Help("Add a new remote")
+	remote.SubCommand("prune").Hidden(true)
+	app._init()
+
+	man, err := app.findSubcommand("man")
+	assert(t, err == nil && man.hidden, "Hidden man subcommand not added")
+
+	app.ParseFrom([]string{"git", "man", dir})
+	_, err = os.Stat(filepath.Join(dir, "git-remote-add.1"))
+	assert(t, err == nil, "Man subcommand did not generate the pages")
+}
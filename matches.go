@@ -1,6 +1,11 @@
 package gommander
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // TODO: Make values to be more explicit, i.e. positional arg matches, matched_cmd_args etc.
 type ParserMatches struct {
@@ -13,23 +18,43 @@ type ParserMatches struct {
 	flagMatches    []flagMatches
 	optionMatches  []optionMatches
 	argMatches     []argMatches
+	parent         *ParserMatches
 }
 
 type flagMatches struct {
 	matchedFlag Flag
+	negated     bool
+	count       int
 	// cursor_index int
 }
 
+// The state of a flag after parsing. Negatable flags can be explicitly turned off, so a flag that was not passed can be told apart from one that was set to false
+type FlagState byte
+
+const (
+	// The flag was not passed
+	FlagNotGiven FlagState = iota
+	// The flag was passed, i.e. `--color`
+	FlagTrue
+	// A negatable flag was turned off, i.e. `--no-color`
+	FlagFalse
+)
+
 type optionMatches struct {
 	matchedOpt    Option
 	instanceCount int
 	passedArgs    []argMatches
+	occurrences   [][]string
+	source        ValueSource
 	// cursor_index   int
 }
 
 type argMatches struct {
 	rawValue   string
+	values     []string
+	value      interface{}
 	instanceOf Argument
+	source     ValueSource
 	// cursor_index int
 }
 
@@ -62,22 +87,98 @@ func (pm *ParserMatches) GetMatchedCommandIndex() int {
 	return pm.matchedCmdIdx
 }
 
-// Returns whether or not a flag was passed to the program args.
-// Accepts the name of the flag, or the short or long version of the flag
-func (pm *ParserMatches) ContainsFlag(val string) bool {
+// Returns the matches of the parent of the matched command, or nil for the matches of the root command. Every command on the path to the matched command keeps its own matches, so the values of an option defined on several commands are kept apart, i.e. for `app --region eu deploy --region us` the matches of `deploy` hold `us` while those of its parent hold `eu`
+func (pm *ParserMatches) Parent() *ParserMatches {
+	return pm.parent
+}
+
+// Returns the matches of the provided command, which must be the matched command or one of its ancestors. Nil is returned for any other command
+func (pm *ParserMatches) ForCommand(cmd *Command) *ParserMatches {
+	for m := pm; m != nil; m = m.parent {
+		if m.matchedCmd == cmd {
+			return m
+		}
+	}
+	return nil
+}
+
+// Returns the level of matches that holds the values of a flag or option: this level if it matched it or its command defines it, otherwise the first ancestor that defines it as global.
+// Global flags and options are therefore readable from the matches of their descendants unless a descendant defines a flag or option with the same value, while the other flags and options of ancestors are only readable from their own matches
+func (pm *ParserMatches) scope(val string) *ParserMatches {
+	if pm.hasSwitchMatch(val) || pm.matchedCmd == nil || pm.matchedCmd.definesSwitch(val) {
+		return pm
+	}
+	for m := pm.parent; m != nil; m = m.parent {
+		if m.matchedCmd != nil && m.matchedCmd.definesGlobalSwitch(val) {
+			return m
+		}
+	}
+	return pm
+}
+
+func (pm *ParserMatches) hasSwitchMatch(val string) bool {
 	for _, v := range pm.flagMatches {
 		flag := v.matchedFlag
 		if flag.ShortVal == val || flag.LongVal == val || flag.Name == val {
 			return true
 		}
 	}
+	for _, v := range pm.optionMatches {
+		opt := v.matchedOpt
+		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether the help flag was passed on any level, so both `app deploy --help` and `app --help deploy` print the help of `deploy`
+func (pm *ParserMatches) helpRequested() bool {
+	for m := pm; m != nil; m = m.parent {
+		if m.ContainsFlag("help") {
+			return true
+		}
+	}
 	return false
 }
 
+// Returns whether or not a flag was passed to the program args. Negatable flags that were turned off, i.e. via `--no-color`, are not considered to be present.
+// Accepts the name of the flag, or the short or long version of the flag
+func (pm *ParserMatches) ContainsFlag(val string) bool {
+	return pm.GetFlagState(val) == FlagTrue
+}
+
+// Returns the number of times a flag was passed, including occurrences within clustered short flags, i.e. 3 for both `-v -v -v` and `-vvv`. Turning off a negatable flag resets its count.
+// Accepts the name of the flag, or the short or long version of the flag
+func (pm *ParserMatches) GetFlagCount(val string) int {
+	for _, v := range pm.scope(val).flagMatches {
+		flag := v.matchedFlag
+		if flag.ShortVal == val || flag.LongVal == val || flag.Name == val {
+			return v.count
+		}
+	}
+	return 0
+}
+
+// Returns whether a flag was set, turned off via its negated value, or not passed at all.
+// Accepts the name of the flag, or the short or long version of the flag
+func (pm *ParserMatches) GetFlagState(val string) FlagState {
+	for _, v := range pm.scope(val).flagMatches {
+		flag := v.matchedFlag
+		if flag.ShortVal == val || flag.LongVal == val || flag.Name == val {
+			if v.negated {
+				return FlagFalse
+			}
+			return FlagTrue
+		}
+	}
+	return FlagNotGiven
+}
+
 // Returns whether or not an option was passed to the program args
 // Accepts as input the name of the option, or its short or long version
 func (pm *ParserMatches) ContainsOption(val string) bool {
-	for _, v := range pm.optionMatches {
+	for _, v := range pm.scope(val).optionMatches {
 		opt := v.matchedOpt
 		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
 			return true
@@ -101,14 +202,30 @@ func (pm *ParserMatches) GetArgValue(val string) (string, error) {
 	return "", errors.New("no value found for provided argument")
 }
 
+// Returns all the values passed to an argument. Variadic arguments return each of the values passed to them, while other arguments return a slice containing their single value.
+// An empty slice is returned if no value was found
+func (pm *ParserMatches) GetArgValues(val string) []string {
+	for _, v := range pm.argMatches {
+		arg := v.instanceOf
+		if arg.Name == val || arg.getRawValue() == val {
+			return v.values
+		}
+	}
+
+	return []string{}
+}
+
 // This method returns the value passed to an option, if any.
-// An error is thrown if no such option exists
+// An error is thrown if no such option exists, or if the option takes no arguments
 // If an option has a default value and none was provided, the default value is used.
+// For options taking several arguments, i.e. `--range <from> <to>`, the value of the first argument is returned. The values of variadic and delimited arguments are joined with spaces, i.e. `a b c` for `--tags a,b,c`. Use `.GetAllOptionInstances()` or `.GetOptionOccurrences()` for the separate values
 func (pm *ParserMatches) GetOptionValue(val string) (string, error) {
-	for _, v := range pm.optionMatches {
+	for _, v := range pm.scope(val).optionMatches {
 		opt := v.matchedOpt
 		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
-			// TODO: Probably check if slice is empty
+			if len(v.passedArgs) == 0 {
+				return "", fmt.Errorf("no value passed to the option: `%v`", opt.LongVal)
+			}
 			return v.passedArgs[0].rawValue, nil
 		}
 	}
@@ -119,13 +236,154 @@ func (pm *ParserMatches) GetOptionValue(val string) (string, error) {
 // For example, `-p 80 -p 90 -p 100`. All these instances are stored in a single slice to be acquired via this method
 func (pm *ParserMatches) GetAllOptionInstances(val string) []string {
 	instances := []string{}
-	for _, v := range pm.optionMatches {
+	for _, v := range pm.scope(val).optionMatches {
 		opt := v.matchedOpt
 		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
 			for _, a := range v.passedArgs {
-				instances = append(instances, a.rawValue)
+				instances = append(instances, a.values...)
 			}
 		}
 	}
 	return instances
 }
+
+// Returns the values passed to an option grouped by occurrence. It is mostly useful for options taking several values per occurrence, i.e. `--range 1 5 --range 7 9` returns `[[1 5] [7 9]]`
+func (pm *ParserMatches) GetOptionOccurrences(val string) [][]string {
+	for _, v := range pm.scope(val).optionMatches {
+		opt := v.matchedOpt
+		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
+			return v.occurrences
+		}
+	}
+	return [][]string{}
+}
+
+// Returns the `key=value` pairs passed to a map option, i.e. `-D key=value -D k2=v2`. When a key is passed more than once, the last value wins
+func (pm *ParserMatches) GetOptionMap(val string) map[string]string {
+	entries := make(map[string]string)
+	for _, v := range pm.GetAllOptionInstances(val) {
+		if key, value, found := strings.Cut(v, "="); found {
+			entries[key] = value
+		}
+	}
+	return entries
+}
+
+// Returns the layer from which the value of an option or argument was acquired, i.e. the command line, an environment variable, the config file or a default value.
+// Accepts the name of the option or argument, or the short or long version of the option. An error is returned if no value was found
+func (pm *ParserMatches) GetValueSource(val string) (ValueSource, error) {
+	for _, v := range pm.scope(val).optionMatches {
+		opt := v.matchedOpt
+		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
+			return v.source, nil
+		}
+	}
+
+	for _, v := range pm.argMatches {
+		arg := v.instanceOf
+		if arg.Name == val || arg.getRawValue() == val {
+			return v.source, nil
+		}
+	}
+
+	return SourceCommandLine, errors.New("no value found for the provided option or argument")
+}
+
+/****************************** Typed value getters ****************************/
+
+// Returns the value of an option or argument as an int. The option or argument must be declared with the `int` type, i.e. `<int:port>`
+func (pm *ParserMatches) GetInt(val string) (int, error) {
+	return Get[int](pm, val)
+}
+
+// Returns the value of an option or argument declared with the `uint` type
+func (pm *ParserMatches) GetUint(val string) (uint, error) {
+	return Get[uint](pm, val)
+}
+
+// Returns the value of an option or argument declared with the `float` type
+func (pm *ParserMatches) GetFloat(val string) (float64, error) {
+	return Get[float64](pm, val)
+}
+
+// Returns the value of an option or argument declared with the `bool` type
+func (pm *ParserMatches) GetBool(val string) (bool, error) {
+	return Get[bool](pm, val)
+}
+
+// Returns the value of an option or argument declared with the `duration` type, i.e. `<duration:timeout>`. Values are parsed using `time.ParseDuration`
+func (pm *ParserMatches) GetDuration(val string) (time.Duration, error) {
+	return Get[time.Duration](pm, val)
+}
+
+// Returns all the values passed to an option or argument regardless of its declared type. For options, every instance is included, i.e. `-p 80 -p 90`, while variadic arguments return each of their values
+func (pm *ParserMatches) GetStringSlice(val string) ([]string, error) {
+	for _, v := range pm.scope(val).optionMatches {
+		opt := v.matchedOpt
+		if opt.ShortVal == val || opt.LongVal == val || opt.Name == val {
+			return pm.GetAllOptionInstances(val), nil
+		}
+	}
+
+	for _, v := range pm.argMatches {
+		arg := v.instanceOf
+		if arg.Name == val || arg.getRawValue() == val {
+			return v.values, nil
+		}
+	}
+
+	return nil, errors.New("no value found for the provided option or argument")
+}
+
+// A generic getter for the value of an option or argument. The type parameter must match the declared type of the argument:
+// `int`, `uint`, `float64`, `bool`, `time.Duration` or `string`. A `[]string` can be requested for any option or argument, see `GetStringSlice`.
+//
+//	port, err := gommander.Get[int](matches, "port")
+func Get[T any](pm *ParserMatches, val string) (T, error) {
+	var zero T
+
+	if slice, ok := interface{}(&zero).(*[]string); ok {
+		values, err := pm.GetStringSlice(val)
+		*slice = values
+		return zero, err
+	}
+
+	match, err := pm.findArgMatch(val)
+	if err != nil {
+		return zero, err
+	}
+
+	value := match.value
+	if len(match.rawValue) == 0 {
+		return zero, fmt.Errorf("no value was passed to: `%v`", val)
+	} else if value == nil {
+		if value, err = convertValue(match.instanceOf.ArgType, match.rawValue); err != nil {
+			return zero, fmt.Errorf("failed to get the value of `%v`: %v", val, err)
+		}
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("cannot get `%v` as type `%T`, it is declared as type: `%v`", val, zero, match.instanceOf.ArgType)
+	}
+	return typed, nil
+}
+
+// Returns the matched value of an option or argument. Options are checked first
+func (pm *ParserMatches) findArgMatch(val string) (*argMatches, error) {
+	for _, v := range pm.scope(val).optionMatches {
+		opt := v.matchedOpt
+		if (opt.ShortVal == val || opt.LongVal == val || opt.Name == val) && len(v.passedArgs) > 0 {
+			return &v.passedArgs[0], nil
+		}
+	}
+
+	for i, v := range pm.argMatches {
+		arg := v.instanceOf
+		if arg.Name == val || arg.getRawValue() == val {
+			return &pm.argMatches[i], nil
+		}
+	}
+
+	return nil, errors.New("no value found for the provided option or argument")
+}
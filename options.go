@@ -6,12 +6,26 @@ import (
 )
 
 type Option struct {
-	Name       string
-	HelpStr    string
-	ShortVal   string
-	LongVal    string
-	Arg        *Argument
-	IsRequired bool
+	Name     string
+	HelpStr  string
+	ShortVal string
+	LongVal  string
+	// The argument of the option. For options taking several values per occurrence, this is the first of the arguments in `Args`
+	Arg *Argument
+	// The arguments of the option, i.e. `<from>` and `<to>` for `--range <from> <to>`
+	Args         []*Argument
+	IsRequired   bool
+	EnvVar       string
+	CompletionFn CompletionCallback
+	// Whether the option collects `key=value` pairs, i.e. `-D key=value -D k2=v2`
+	IsMap           bool
+	ValidKeys       []string
+	KeyValidatorFns map[string]func(string) error
+	// Whether the value of the option is optional, i.e. `--color[=<when>]`. Optional values must be attached to the option
+	IsValueOptional bool
+	// The value used when an option with an optional value is passed without one
+	ImplicitValue string
+	IsGlobal      bool
 }
 
 // A builder method to generate a new option
@@ -40,7 +54,19 @@ func (o *Option) Required(val bool) *Option {
 	return o
 }
 
-// A method for adding a new argument to an option. Takes as input the name of the argument
+// Binds the option to an environment variable. When the option is not passed to the program, its value is read from the environment variable if it is set
+func (o *Option) Env(val string) *Option {
+	o.EnvVar = val
+	return o
+}
+
+// A method for setting a callback that provides completion candidates for the option value at runtime. If none is set, the completion callback of the option argument is used instead
+func (o *Option) CompletionFunc(fn CompletionCallback) *Option {
+	o.CompletionFn = fn
+	return o
+}
+
+// A method for adding a new argument to an option. Takes as input the name of the argument. Options taking several values per occurrence are declared by adding more than one argument, i.e. `--range <from> <to>`
 func (o *Option) Argument(val string) *Option {
 	o.AddArgument(newArgument(val, ""))
 	return o
@@ -48,25 +74,114 @@ func (o *Option) Argument(val string) *Option {
 
 // A builder method for adding an argument. Expects an instance of an argument as input
 func (o *Option) AddArgument(arg *Argument) *Option {
-	id := fmt.Sprintf("opt-%s-arg-%s", o.Name, arg.Name)
-	if !cache[id] {
-		cache[id] = true
+	if o.Arg == nil {
 		o.Arg = arg
 	}
+	o.Args = append(o.Args, arg)
+	return o
+}
+
+// Sets a delimiter used to split the values passed to the arguments of the option, i.e. `--tags a,b,c`. It should be invoked after the arguments of the option are added
+func (o *Option) Delimiter(val string) *Option {
+	for _, a := range o.arguments() {
+		a.Delimiter(val)
+	}
+	return o
+}
+
+// Configures the option to collect `key=value` pairs, i.e. `-D key=value -D k2=v2`, which are acquired via the `ParserMatches.GetOptionMap()` method. An argument displayed as `<key=value>` is added if the option has none
+func (o *Option) Map(val bool) *Option {
+	o.IsMap = val
+	if val && o.Arg == nil {
+		o.AddArgument(NewArgument("<key=value>"))
+	}
+	return o
+}
+
+// A method for setting an option as global. Like global flags, global options apply to all the descendants of the command on which they are defined and can be passed before or after the names of subcommands, i.e. `app --log-level debug deploy` or `app deploy --log-level debug`
+func (o *Option) Global(val bool) *Option {
+	o.IsGlobal = val
+	return o
+}
+
+// Makes the value of the option optional, i.e. `--color` or `--color=never`. Optional values are only accepted when attached to the option, as in `--color=never` or `-cnever`, so a following argument is never consumed by mistake.
+// The provided value is used when the option is passed without a value, while the default value of the option argument is still used when the option is not passed at all. An option can also be declared with an optional value by passing `--color[=<when>]` to the `.Option()` method
+func (o *Option) OptionalValue(implicit string) *Option {
+	o.IsValueOptional = true
+	o.ImplicitValue = implicit
+	return o
+}
+
+// Restricts the keys that can be passed to a map option
+func (o *Option) ValidateKeys(keys []string) *Option {
+	o.ValidKeys = keys
 	return o
 }
 
+// Sets a validator function for the values passed to a key of a map option, i.e. an `int` check for `-D port=8080`
+func (o *Option) KeyValidatorFunc(key string, fn func(string) error) *Option {
+	if o.KeyValidatorFns == nil {
+		o.KeyValidatorFns = make(map[string]func(string) error)
+	}
+	o.KeyValidatorFns[key] = fn
+	return o
+}
+
+// Returns the arguments of the option, whether they were added via the builder methods or set directly on the `Arg` field
+func (o *Option) arguments() []*Argument {
+	if len(o.Args) > 0 {
+		return o.Args
+	}
+	if o.Arg != nil {
+		return []*Argument{o.Arg}
+	}
+	return []*Argument{}
+}
+
+// Returns the arguments of the option as displayed in help output, i.e. `<from> <to>` or `<tag>[,<tag>...]`
+func (o *Option) displayArgs() string {
+	values := []string{}
+	for _, a := range o.arguments() {
+		values = append(values, a.displayValue())
+	}
+	return strings.Join(values, " ")
+}
+
+// Returns the environment variable bound to the option, or one derived from the env prefix of the app if any
+func (o *Option) getEnvVar(app *Command) string {
+	if len(o.EnvVar) > 0 {
+		return o.EnvVar
+	}
+	if app == nil || len(app.envPrefix) == 0 || len(o.Name) == 0 {
+		return ""
+	}
+
+	name := strings.TrimSuffix(app.envPrefix, "_") + "_" + o.Name
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
 func newOption(val string, help string, required bool) Option {
 	opt := Option{HelpStr: help, IsRequired: required}
 	values := strings.Split(val, " ")
 
 	for _, v := range values {
+		if long, arg, found := strings.Cut(v, "[="); found && strings.HasPrefix(v, "-") {
+			// optional values, i.e. `--color[=<when>]`
+			arg = strings.TrimSuffix(arg, "]")
+			if !strings.HasPrefix(arg, "<") {
+				arg = "<" + arg + ">"
+			}
+			opt.AddArgument(newArgument(arg, ""))
+			opt.IsValueOptional = true
+			v = long
+		}
+
 		if strings.HasPrefix(v, "--") {
 			opt.LongVal = v
 		} else if strings.HasPrefix(v, "-") {
 			opt.ShortVal = v
 		} else {
-			opt.Arg = newArgument(v, "")
+			opt.AddArgument(newArgument(v, ""))
 		}
 	}
 	opt.Name = strings.TrimPrefix(opt.LongVal, "--")
@@ -84,17 +199,31 @@ func (o *Option) generate(app *Command) (string, string) {
 	}
 
 	if len(o.LongVal) > 0 {
-		leading.WriteString(" " + o.LongVal + " ")
+		leading.WriteString(" " + o.LongVal)
+		if !o.IsValueOptional {
+			leading.WriteString(" ")
+		}
 	}
 
-	if o.Arg != nil {
-		leading.WriteString(fmt.Sprintf("%v ", o.Arg.getRawValue()))
+	if o.Arg != nil && o.IsValueOptional {
+		leading.WriteString(fmt.Sprintf("[=%v] ", o.displayArgs()))
+	} else if o.Arg != nil {
+		leading.WriteString(fmt.Sprintf("%v ", o.displayArgs()))
 	}
 
 	floating.WriteString(o.HelpStr)
+	if o.IsValueOptional && len(o.ImplicitValue) > 0 {
+		floating.WriteString(fmt.Sprintf(" (if no value: %v)", o.ImplicitValue))
+	}
+	if len(o.ValidKeys) > 0 {
+		floating.WriteString(fmt.Sprintf(" (keys: %v)", strings.Join(o.ValidKeys, ", ")))
+	}
 	if o.Arg != nil && o.Arg.hasDefaultValue() {
 		floating.WriteString(fmt.Sprintf(" (default: %v)", o.Arg.DefaultValue))
 	}
+	if env := o.getEnvVar(app); len(env) > 0 {
+		floating.WriteString(fmt.Sprintf(" [env: %v]", env))
+	}
 
 	return leading.String(), floating.String()
 }
@@ -83,3 +83,36 @@ func BenchmarkOptGenerateFn(b *testing.B) {
 		o.generate(&c)
 	}
 }
+
+func TestOptionValueShapes(t *testing.T) {
+	{
+		opt := newOption("--range <from> <to>", "A range of values", false)
+		assertEq(t, len(opt.Args), 2, "Option arguments not all added")
+		assertEq(t, opt.Arg, opt.Args[0], "First option argument not set")
+
+		gotL, _ := opt.generate(App())
+		assertEq(t, gotL, "    --range <from> <to> ", "Options with several arguments displayed incorrectly")
+	}
+	{
+		opt := NewOption("tags").Argument("<tag>").Delimiter(",")
+		gotL, _ := opt.generate(App())
+		assertEq(t, gotL, "    --tags <tag>[,<tag>...] ", "Options with a delimiter displayed incorrectly")
+	}
+	{
+		opt := NewOption("define").Short('D').Map(true).ValidateKeys([]string{"env", "port"})
+		gotL, gotF := opt.generate(App())
+		assertEq(t, gotL, "-D, --define <key=value> ", "Map options displayed incorrectly")
+		assertEq(t, gotF, " (keys: env, port)", "Valid keys of map options not displayed")
+	}
+	{
+		opt := newOption("-c --color[=WHEN]", "When to use colors", false)
+		assert(t, opt.IsValueOptional, "Optional value syntax not parsed")
+		assertEq(t, opt.LongVal, "--color", "Long value of an option with an optional value parsed incorrectly")
+		assertEq(t, opt.Arg.Name, "WHEN", "Argument of an option with an optional value parsed incorrectly")
+
+		opt.OptionalValue("always")
+		gotL, gotF := opt.generate(App())
+		assertEq(t, gotL, "-c, --color[=<WHEN>] ", "Options with optional values displayed incorrectly")
+		assertEq(t, gotF, "When to use colors (if no value: always)", "Implicit value of an option not displayed")
+	}
+}
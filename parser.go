@@ -3,6 +3,7 @@ package gommander
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -12,9 +13,10 @@ type Parser struct {
 	rootCmd      *Command
 	currentCmd   *Command
 	matches      ParserMatches
-	eaten        []string
+	eaten        []int
 	cmdIdx       int
 	currentToken string
+	config       configValues
 }
 
 func NewParser(entry *Command) Parser {
@@ -23,9 +25,10 @@ func NewParser(entry *Command) Parser {
 		rootCmd:    entry,
 		currentCmd: entry,
 		matches: ParserMatches{
-			argCount:   0,
-			rootCmd:    entry,
-			matchedCmd: entry,
+			argCount:      0,
+			rootCmd:       entry,
+			matchedCmd:    entry,
+			matchedCmdIdx: -1,
 		},
 	}
 }
@@ -48,25 +51,95 @@ func (p *Parser) isSpecialValue(val string) bool {
 	return val == "-h" || val == "--help"
 }
 
-func (p *Parser) isPosixFlagSyntax(vals []string) bool {
-	return len(vals) > 2 && vals[0] == "-" && vals[1] != "-"
+func (p *Parser) isShortClusterSyntax(val string) bool {
+	return len(val) > 2 && val[0] == '-' && val[1] != '-'
 }
 
 func (p *Parser) getFlag(val string) (*Flag, error) {
-	for _, f := range p.currentCmd.flags {
-		if f.ShortVal == val || f.LongVal == val {
+	for _, f := range p.currentCmd.allFlags() {
+		if f.ShortVal == val || f.LongVal == val || f.negatedLongVal() == val {
 			return f, nil
 		}
 	}
+	if matches := p.longPrefixMatches(val); len(matches) == 1 {
+		for _, f := range p.currentCmd.allFlags() {
+			if f.LongVal == matches[0] || f.negatedLongVal() == matches[0] {
+				return f, nil
+			}
+		}
+	}
 	return NewFlag(""), errors.New("flag not found")
 }
 
+// Checks whether a value resolved to a negatable flag turns the flag off, either directly via `--no-color` or via an unambiguous prefix of it
+func (p *Parser) isNegated(val string, flag *Flag) bool {
+	if !flag.IsNegatable {
+		return false
+	}
+	if val == flag.negatedLongVal() {
+		return true
+	}
+	matches := p.longPrefixMatches(val)
+	return len(matches) == 1 && matches[0] == flag.negatedLongVal()
+}
+
+// Records a matched flag. Repeated flags are counted, and the last instance of a negatable flag wins, i.e. `--color --no-color` turns the flag off
+func (p *Parser) matchFlag(flag *Flag, negated bool) {
+	count := 1
+	if negated {
+		count = 0
+	}
+
+	level := p.levelOf(p.currentCmd.flagOwner(flag))
+	for i, m := range level.flagMatches {
+		if m.matchedFlag.ShortVal == flag.ShortVal && m.matchedFlag.LongVal == flag.LongVal {
+			if !negated {
+				count += m.count
+			}
+			level.flagMatches[i].negated = negated
+			level.flagMatches[i].count = count
+			return
+		}
+	}
+	level.flagMatches = append(level.flagMatches, flagMatches{matchedFlag: *flag, negated: negated, count: count})
+}
+
+// Starts a new level of matches for a matched subcommand, keeping the matches of the previous command as its parent
+func (p *Parser) descend(sc *Command, index int) {
+	level := p.matches
+	p.matches = ParserMatches{
+		argCount:      level.argCount,
+		rawArgs:       level.rawArgs,
+		rootCmd:       level.rootCmd,
+		matchedCmd:    sc,
+		matchedCmdIdx: index,
+		parent:        &level,
+	}
+	p.currentCmd = sc
+	p.cmdIdx = index
+}
+
+// Returns the level of matches of a command on the path to the current command. Flags and options are recorded on the level of the command that defines them, so global ones are resolved the same way whether they are passed before or after the names of subcommands
+func (p *Parser) levelOf(cmd *Command) *ParserMatches {
+	if level := p.matches.ForCommand(cmd); level != nil {
+		return level
+	}
+	return &p.matches
+}
+
 func (p *Parser) getOption(val string) (*Option, error) {
-	for _, o := range p.currentCmd.options {
+	for _, o := range p.currentCmd.allOptions() {
 		if o.ShortVal == val || o.LongVal == val {
 			return o, nil
 		}
 	}
+	if matches := p.longPrefixMatches(val); len(matches) == 1 {
+		for _, o := range p.currentCmd.allOptions() {
+			if o.LongVal == matches[0] {
+				return o, nil
+			}
+		}
+	}
 	return NewOption(""), errors.New("no option found")
 }
 
@@ -85,16 +158,82 @@ func (p *Parser) getSubCommand(val string) (*Command, error) {
 			return s, nil
 		}
 	}
+	if matches := p.subCmdPrefixMatches(val); len(matches) == 1 {
+		return matches[0], nil
+	}
 	return NewCommand(""), errors.New("no subcmd found")
 }
 
-func (p *Parser) _eat(val string) {
-	p.eaten = append(p.eaten, val)
+// Returns the long values of the flags and options of the current command that begin with the provided value when the `AllowPrefixMatching` setting is enabled
+func (p *Parser) longPrefixMatches(val string) []string {
+	matches := []string{}
+	if !p.rootCmd.settings[AllowPrefixMatching] || !strings.HasPrefix(val, "--") || len(val) < 3 {
+		return matches
+	}
+
+	for _, f := range p.currentCmd.allFlags() {
+		for _, v := range []string{f.LongVal, f.negatedLongVal()} {
+			if len(v) > 0 && strings.HasPrefix(v, val) {
+				matches = append(matches, v)
+			}
+		}
+	}
+	for _, o := range p.currentCmd.allOptions() {
+		if strings.HasPrefix(o.LongVal, val) {
+			matches = append(matches, o.LongVal)
+		}
+	}
+	return matches
+}
+
+// Returns the visible subcommands of the current command whose name or one of whose aliases begins with the provided value when the `AllowPrefixMatching` setting is enabled
+func (p *Parser) subCmdPrefixMatches(val string) []*Command {
+	matches := []*Command{}
+	if !p.rootCmd.settings[AllowPrefixMatching] || len(val) == 0 {
+		return matches
+	}
+
+	for _, s := range p.currentCmd.visibleSubCommands() {
+		for _, n := range append([]string{s.name}, s.aliases...) {
+			if strings.HasPrefix(n, val) {
+				matches = append(matches, s)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// Returns an `AmbiguousPrefix` error if the value is a prefix of more than one long flag or option of the current command
+func (p *Parser) ambiguousSwitch(val string) *Error {
+	if matches := p.longPrefixMatches(val); len(matches) > 1 {
+		err := generateError(p.currentCmd, AmbiguousPrefix, append([]string{val}, matches...))
+		return &err
+	}
+	return nil
 }
 
-func (p *Parser) _isEaten(val string) bool {
+// Returns an `AmbiguousPrefix` error if the value is a prefix of more than one subcommand of the current command
+func (p *Parser) ambiguousSubCmd(val string) *Error {
+	if matches := p.subCmdPrefixMatches(val); len(matches) > 1 {
+		args := []string{val}
+		for _, s := range matches {
+			args = append(args, s.name)
+		}
+		err := generateError(p.currentCmd, AmbiguousPrefix, args)
+		return &err
+	}
+	return nil
+}
+
+// Marks the token at the provided position in the command line as consumed. Tokens are tracked by position rather than by value since the same value can be passed more than once, i.e. `--range 1 1`
+func (p *Parser) _eat(index int) {
+	p.eaten = append(p.eaten, index)
+}
+
+func (p *Parser) _isEaten(index int) bool {
 	for _, v := range p.eaten {
-		if v == val {
+		if v == index {
 			return true
 		}
 	}
@@ -102,8 +241,18 @@ func (p *Parser) _isEaten(val string) bool {
 	return false
 }
 
+// Returns the tokens that follow the one at the provided position in the command line, along with their positions
+func (p *Parser) following(index int) ([]string, []int) {
+	args := p.matches.rawArgs[index+1:]
+	positions := []int{}
+	for i := range args {
+		positions = append(positions, index+1+i)
+	}
+	return args, positions
+}
+
 func (p *Parser) reset() {
-	p.eaten = []string{}
+	p.eaten = []int{}
 	p.cursor = 0
 	p.cmdIdx = -1
 }
@@ -121,89 +270,90 @@ func (p *Parser) parse(rawArgs []string) (*ParserMatches, *Error) {
 		p.cursor = index
 		p.currentToken = arg
 
+		// values already consumed by a preceding option
+		if p._isEaten(index) {
+			continue
+		}
+
 		if p.isFlagLike(arg) {
 			if flag, err := p.getFlag(arg); err == nil {
 				// handle is flag
-				p._eat(arg)
+				p._eat(index)
 				if !allowPositionalArgs {
-					flagCfg := flagMatches{
-						matchedFlag: *flag,
-					}
-					if !p.matches.ContainsFlag(flag.LongVal) {
-						p.matches.flagMatches = append(p.matches.flagMatches, flagCfg)
-					}
+					p.matchFlag(flag, p.isNegated(arg, flag))
 				}
 			} else if opt, err := p.getOption(arg); err == nil {
 				// Handle is option
-				p._eat(arg)
-				err := p.parseOption(opt, rawArgs[(index+1):])
+				p._eat(index)
+				if opt.IsValueOptional {
+					// optional values must be attached, so the following arguments are left alone
+					if err := p.parseImplicitValue(opt); err != nil {
+						return &p.matches, err
+					}
+					continue
+				}
+				args, positions := p.following(index)
+				err := p.parseOption(opt, args, positions, SourceCommandLine)
 				if err != nil {
 					return &p.matches, err
 				}
 			} else if arg == "--" {
-				p._eat(arg)
+				p._eat(index)
 				allowPositionalArgs = true
 			} else if p.isLongOptSyntax(arg) && !allowPositionalArgs {
 				// parse special option
-				p._eat(arg)
-				parts := strings.Split(arg, "=")
+				p._eat(index)
+				// only the first `=` separates the option from its value, i.e. `--define=key=value`
+				name, value, _ := strings.Cut(arg, "=")
 
-				opt, err := p.getOption(parts[0])
+				opt, err := p.getOption(name)
 				if err != nil {
-					err := generateError(p.currentCmd, UnknownOption, []string{parts[0], arg})
+					if err := p.ambiguousSwitch(name); err != nil {
+						return &p.matches, err
+					}
+					err := generateError(p.currentCmd, UnknownOption, []string{name, arg})
 					return &p.matches, &err
 				}
 
-				temp := []string{parts[1]}
-				temp = append(temp, rawArgs[(index+1):]...)
+				// the attached value has no position of its own
+				temp, positions := []string{value}, []int{-1}
+				if !opt.IsValueOptional {
+					args, following := p.following(index)
+					temp = append(temp, args...)
+					positions = append(positions, following...)
+				}
 
-				e := p.parseOption(opt, temp)
+				e := p.parseOption(opt, temp, positions, SourceCommandLine)
 				if e != nil {
 					return &p.matches, e
 				}
 			} else if allowPositionalArgs {
-				p._eat(arg)
+				p._eat(index)
 				p.matches.positionalArgs = append(p.matches.positionalArgs, arg)
-			} else if strings.ContainsRune(arg, '=') {
-				err := generateError(p.currentCmd, UnknownOption, []string{})
-				return &p.matches, &err
-			} else if !p._isEaten(arg) && !allowPositionalArgs {
-				values := strings.Split(arg, "")
-
-				// TODO: More validation
-				if p.isPosixFlagSyntax(values) {
-					p._eat(arg)
-					for _, v := range values[1:] {
-						flag, err := p.getFlag(fmt.Sprintf("-%v", v))
-
-						if err != nil {
-							err := generateError(p.currentCmd, UnknownOption, []string{v, p.currentToken, ""})
-							return &p.matches, &err
-						}
-
-						flagCfg := flagMatches{
-							matchedFlag: *flag,
-						}
-						if !p.matches.ContainsFlag(flag.LongVal) {
-							p.matches.flagMatches = append(p.matches.flagMatches, flagCfg)
-						}
+			} else if !allowPositionalArgs {
+				if p.isShortClusterSyntax(arg) {
+					p._eat(index)
+					if err := p.parseShortCluster(arg, index); err != nil {
+						return &p.matches, err
 					}
 					continue
 				}
 
+				if err := p.ambiguousSwitch(p.currentToken); err != nil {
+					return &p.matches, err
+				}
 				err := generateError(p.currentCmd, UnknownOption, []string{p.currentToken})
 				return &p.matches, &err
 			}
 		} else if sc, err := p.getSubCommand(arg); err == nil {
 			// handle subcmd
-			p._eat(arg)
-			p.currentCmd = sc
-			p.cmdIdx = index
+			p._eat(index)
+			p.descend(sc, index)
 
 			continue
 		} else if allowPositionalArgs {
 			// TODO: More conditionals
-			p._eat(arg)
+			p._eat(index)
 			p.matches.positionalArgs = append(p.matches.positionalArgs, arg)
 		}
 	}
@@ -211,64 +361,190 @@ func (p *Parser) parse(rawArgs []string) (*ParserMatches, *Error) {
 	p.matches.matchedCmd = p.currentCmd
 	p.matches.matchedCmdIdx = p.cmdIdx
 
-	cmdArgs := []string{}
-	if p.cmdIdx == -1 {
-		// No subcommands matched
-		cmdArgs = rawArgs
-	} else if len(rawArgs) > p.cmdIdx+1 {
-		cmdArgs = append(cmdArgs, rawArgs[p.cmdIdx+1:]...)
-	}
+	// the arguments following the matched subcommand, or all of them if no subcommands matched
+	cmdArgs, positions := p.following(p.cmdIdx)
 
-	err := p.parseCmd(cmdArgs)
+	err := p.parseCmd(cmdArgs, positions)
 	if err != nil {
 		return &p.matches, err
 	}
 
-	if !p.matches.ContainsFlag("help") {
-		for _, o := range p.currentCmd.options {
-			if o.IsRequired && !p.matches.ContainsOption(o.LongVal) {
-				var argVals []string
-				if o.Arg != nil {
-					a := o.Arg
-					if len(a.DefaultValue) == 0 {
-						// No default value and value is required
-						err := generateError(p.currentCmd, MissingRequiredOption, []string{o.LongVal})
-						return &p.matches, &err
-					}
-					// Generate opt match with default value
-					argVals = append(argVals, a.DefaultValue)
+	if !p.matches.helpRequested() {
+		if err := p.loadConfig(); err != nil {
+			return &p.matches, err
+		}
+
+		// each command on the path falls back for its own options, so those of ancestors are filled in as well
+		path := []*Command{}
+		for c := p.currentCmd; c != nil; c = c.parent {
+			path = append([]*Command{c}, path...)
+		}
+		for _, c := range path {
+			if err := p.fillOptions(c); err != nil {
+				return &p.matches, err
+			}
+		}
+
+		if err := p.checkOptionGroups(); err != nil {
+			return &p.matches, err
+		}
+	}
+
+	return &p.matches, nil
+}
+
+// Fills in the options of a command on the matched path that were not passed, from the environment, the config file or their default values, and reports required options that are still missing
+func (p *Parser) fillOptions(cmd *Command) *Error {
+	level := p.levelOf(cmd)
+	for _, o := range cmd.options {
+		if level.ContainsOption(o.LongVal) {
+			continue
+		}
+
+		// Fallback to the environment variable bound to the option, if any
+		if env := o.getEnvVar(p.rootCmd); len(env) > 0 {
+			if val, exists := os.LookupEnv(env); exists {
+				if err := p.parseOption(o, []string{val}, nil, SourceEnv); err != nil {
+					return err
 				}
+				continue
+			}
+		}
 
-				err := p.parseOption(o, argVals)
-				if err != nil {
-					return &p.matches, err
+		// Then to the values in the config file, if any
+		if vals, exists := p.config[joinConfigKey(cmd.configSection(), o.Name)]; exists && len(o.Name) > 0 {
+			for _, v := range vals {
+				if err := p.parseOption(o, []string{v}, nil, SourceConfig); err != nil {
+					return err
 				}
 			}
+			continue
 		}
 
+		if o.IsRequired {
+			var argVals []string
+			if o.Arg != nil {
+				a := o.Arg
+				if len(a.DefaultValue) == 0 {
+					// No default value and value is required
+					err := generateError(p.currentCmd, MissingRequiredOption, []string{o.LongVal})
+					return &err
+				}
+				// Generate opt match with default value
+				argVals = append(argVals, a.DefaultValue)
+			}
+
+			err := p.parseOption(o, argVals, nil, SourceDefault)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return &p.matches, nil
+	return nil
 }
 
-func (p *Parser) parseOption(opt *Option, rawArgs []string) *Error {
-	argList := []*Argument{}
-	if opt.Arg != nil {
-		argList = append(argList, opt.Arg)
+// Loads the config file passed via the `--config` option, or the one found in the default locations if the program is configured to use config files
+func (p *Parser) loadConfig() *Error {
+	app := p.rootCmd
+	if len(app.configName) == 0 || p.config != nil {
+		return nil
+	}
+
+	path := ""
+	if val, err := p.matches.GetOptionValue("config"); err == nil {
+		path = val
+	} else if opt, err := app.findOption("--config"); err == nil {
+		if env := opt.getEnvVar(app); len(env) > 0 {
+			path = os.Getenv(env)
+		}
+	}
+
+	if len(path) == 0 {
+		path = findConfigFile(app.configName)
+	}
+	if len(path) == 0 {
+		p.config = configValues{}
+		return nil
 	}
 
-	args, err := p.getArgMatches(argList, rawArgs)
+	values, e := loadConfigFile(path)
+	if e != nil {
+		err := generateError(p.currentCmd, InvalidConfig, []string{path, e.Error()})
+		return &err
+	}
+
+	p.config = values
+	return nil
+}
+
+// Parses the values of an option. Values read from the command line come with their positions, while values from other sources, i.e. the environment, have none
+func (p *Parser) parseOption(opt *Option, rawArgs []string, positions []int, source ValueSource) *Error {
+	argList := opt.arguments()
+
+	// values of options taking several arguments are separated by whitespace when read from the environment or a config file
+	if source != SourceCommandLine && len(argList) > 1 && len(rawArgs) == 1 {
+		rawArgs = strings.Fields(rawArgs[0])
+	}
+
+	args, err := p.getArgMatches(argList, rawArgs, positions)
 	if err != nil {
 		return err
 	}
 
-	if p.matches.ContainsOption(opt.LongVal) {
-		for i, cfg := range p.matches.optionMatches {
+	return p.recordOption(opt, args, source)
+}
+
+// Records an option with an optional value that was passed without one, using the implicit value of the option
+func (p *Parser) parseImplicitValue(opt *Option) *Error {
+	args := []argMatches{}
+	if opt.Arg != nil {
+		values := []string{}
+		if len(opt.ImplicitValue) > 0 {
+			if err := p.validateArgValue(opt.Arg, opt.ImplicitValue); err != nil {
+				return err
+			}
+			values = append(values, opt.ImplicitValue)
+		}
+
+		argCfg := argMatches{
+			rawValue:   opt.ImplicitValue,
+			values:     values,
+			instanceOf: *opt.Arg,
+			source:     SourceCommandLine,
+		}
+		if len(values) == 1 {
+			argCfg.value, _ = convertValue(opt.Arg.ArgType, values[0])
+		}
+		args = append(args, argCfg)
+	}
+
+	return p.recordOption(opt, args, SourceCommandLine)
+}
+
+func (p *Parser) recordOption(opt *Option, args []argMatches, source ValueSource) *Error {
+	values := []string{}
+	for _, a := range args {
+		values = append(values, a.values...)
+	}
+
+	if opt.IsMap {
+		for _, v := range values {
+			if err := p.validateMapEntry(opt, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	level := p.levelOf(p.currentCmd.optionOwner(opt))
+	if level.ContainsOption(opt.LongVal) {
+		for i, cfg := range level.optionMatches {
 			if cfg.matchedOpt.LongVal == opt.LongVal {
 				cfg.passedArgs = append(cfg.passedArgs, args...)
+				cfg.occurrences = append(cfg.occurrences, values)
 				cfg.instanceCount++
 
-				p.matches.optionMatches[i] = cfg
+				level.optionMatches[i] = cfg
 			}
 		}
 	} else {
@@ -276,31 +552,86 @@ func (p *Parser) parseOption(opt *Option, rawArgs []string) *Error {
 			matchedOpt:    *opt,
 			instanceCount: 1,
 			passedArgs:    args,
+			occurrences:   [][]string{values},
+			source:        source,
 		}
 
-		p.matches.optionMatches = append(p.matches.optionMatches, optCfg)
+		level.optionMatches = append(level.optionMatches, optCfg)
 	}
 
 	return nil
 }
 
-func (p *Parser) parseCmd(rawArgs []string) *Error {
-	argCfgVals, err := p.getArgMatches(p.currentCmd.arguments, rawArgs)
+// Parses a cluster of short flags and options, getopt-style. Flags can be combined, i.e. `-xvf`, and the first option in the cluster takes the rest of the token as its value, with an optional `=` sign, i.e. `-p8080`, `-p=8080` or `-xvfarchive.tar`. The attached value is taken as it is, even when it begins with a dash, i.e. `-f-`. An option at the end of the cluster takes its value from the following arguments instead, i.e. `-xvf archive.tar`
+func (p *Parser) parseShortCluster(cluster string, index int) *Error {
+	chars := []rune(cluster)
+	for i := 1; i < len(chars); i++ {
+		short := fmt.Sprintf("-%c", chars[i])
+
+		if flag, err := p.getFlag(short); err == nil {
+			p.matchFlag(flag, false)
+			continue
+		}
+
+		opt, err := p.getOption(short)
+		if err != nil {
+			err := generateError(p.currentCmd, UnknownOption, []string{string(chars[i]), cluster, strconv.Itoa(i)})
+			return &err
+		}
+
+		if i+1 < len(chars) {
+			if attached := strings.TrimPrefix(string(chars[i+1:]), "="); len(attached) > 0 {
+				return p.parseOption(opt, []string{attached}, nil, SourceCommandLine)
+			}
+			// an empty value after `=` is missing, rather than taken from the following arguments
+			if opt.IsValueOptional {
+				return p.parseImplicitValue(opt)
+			}
+			return p.parseOption(opt, []string{}, nil, SourceCommandLine)
+		} else if opt.IsValueOptional {
+			return p.parseImplicitValue(opt)
+		}
+		args, positions := p.following(index)
+		return p.parseOption(opt, args, positions, SourceCommandLine)
+	}
+	return nil
+}
+
+func (p *Parser) parseCmd(rawArgs []string, positions []int) *Error {
+	// positional arguments are matched in order against the tokens left over by flags and options, wherever they appear, i.e. `--color x.txt`
+	args, free := []string{}, []int{}
+	for i, a := range rawArgs {
+		if !p._isEaten(positions[i]) && !p.isFlagLike(a) {
+			args = append(args, a)
+			free = append(free, positions[i])
+		}
+	}
+
+	list := p.currentCmd.arguments
+	if p.matches.helpRequested() && len(args) < len(list) {
+		// missing arguments are not reported when help is requested
+		list = list[:len(args)]
+	}
+
+	argCfgVals, err := p.getArgMatches(list, args, free)
 	if err != nil {
 		return err
 	}
 
 	// expected no args, probably a subcommand
-	if len(rawArgs) > 0 && len(argCfgVals) == 0 {
-		if p.currentCmd.hasSubcommands() && !p._isEaten(rawArgs[0]) {
-			err := generateError(p.currentCmd, UnknownCommand, []string{rawArgs[0]})
+	if len(args) > 0 && len(argCfgVals) == 0 {
+		if p.currentCmd.hasSubcommands() && !p._isEaten(free[0]) {
+			if err := p.ambiguousSubCmd(args[0]); err != nil {
+				return err
+			}
+			err := generateError(p.currentCmd, UnknownCommand, []string{args[0]})
 			return &err
 		}
 	}
 
 	// any unresolved arguments
-	for _, a := range rawArgs {
-		if !p._isEaten(a) {
+	for i, a := range args {
+		if !p._isEaten(free[i]) {
 			err := generateError(p.currentCmd, UnresolvedArgument, []string{a})
 			return &err
 		}
@@ -310,77 +641,113 @@ func (p *Parser) parseCmd(rawArgs []string) *Error {
 	return nil
 }
 
-func (p *Parser) getArgMatches(list []*Argument, args []string) ([]argMatches, *Error) {
-	// maxLen := len(list)
+// Matches values to a list of arguments. Values read from the command line are consumed by position, so that each token is matched once. Values with no position, either because the list of positions is nil or the position is negative, are always available
+func (p *Parser) getArgMatches(list []*Argument, args []string, positions []int) ([]argMatches, *Error) {
 	matches := []argMatches{}
 
+	// values with no position are taken as they are, so attached values such as `-f-` or `-n-1` are not mistaken for flags
+	literal := func(i int) bool {
+		return positions == nil || positions[i] < 0
+	}
+	available := func(i int) bool {
+		return literal(i) || (!p.isFlagLike(args[i]) && !p._isEaten(positions[i]))
+	}
+	consume := func(i int) {
+		if positions != nil && positions[i] >= 0 {
+			p._eat(positions[i])
+		}
+	}
+
 	for argIdx, argVal := range list {
-		var builder strings.Builder
+		values := []string{}
+		source := SourceCommandLine
+
+		// Values not passed to the program are read from the environment, then from defaults
+		fallback := func() bool {
+			if val, exists := argVal.lookupEnv(); exists {
+				values = append(values, val)
+				source = SourceEnv
+				return true
+			} else if argVal.hasDefaultValue() {
+				values = append(values, argVal.DefaultValue)
+				source = SourceDefault
+				return true
+			}
+			return false
+		}
 
 		if argVal.IsVariadic {
-			for _, v := range args {
-				if !p.isFlagLike(v) && !p._isEaten(v) {
-					p._eat(v)
-					builder.WriteString(v)
-					builder.WriteRune(' ')
+			for i, v := range args {
+				if available(i) {
+					consume(i)
+					values = append(values, v)
 				}
 			}
+			if len(values) == 0 && !fallback() && argVal.IsRequired {
+				args := []string{argVal.getRawValue()}
+				err := generateError(p.currentCmd, MissingRequiredArgument, args)
+
+				return matches, &err
+			}
 		} else if argIdx < len(args) {
 			v := args[argIdx]
 
-			if p.isSpecialValue(v) {
+			if p.isSpecialValue(v) && !literal(argIdx) {
 				break
-			} else if !p.isFlagLike(v) && !p._isEaten(v) {
-				p._eat(v)
-				builder.WriteString(v)
-			} else if argVal.hasDefaultValue() {
-				builder.WriteString(argVal.DefaultValue)
-			} else if argVal.IsRequired {
-				args := []string{argVal.getRawValue(), v}
-				err := generateError(p.currentCmd, MissingRequiredArgument, args)
+			} else if available(argIdx) {
+				consume(argIdx)
+				if len(argVal.ValueDelimiter) > 0 {
+					values = append(values, strings.Split(v, argVal.ValueDelimiter)...)
+				} else {
+					values = append(values, v)
+				}
+			} else if !fallback() {
+				if argVal.IsRequired {
+					args := []string{argVal.getRawValue(), v}
+					err := generateError(p.currentCmd, MissingRequiredArgument, args)
 
-				return matches, &err
-			} else {
+					return matches, &err
+				}
 				continue
 			}
-		} else if argVal.IsRequired {
+		} else if !fallback() && argVal.IsRequired {
 			args := []string{argVal.getRawValue()}
 			err := generateError(p.currentCmd, MissingRequiredArgument, args)
 
 			return matches, &err
 		}
 
-		// test the value against default values if any
-		input := builder.String()
-		if len(input) > 0 && len(argVal.ValidValues) > 0 && !argVal.testValue(input) {
-			args := []string{input}
-			args = append(args, argVal.ValidValues...)
-			err := generateError(p.currentCmd, InvalidArgumentValue, args)
+		// check the number of values passed to variadic arguments
+		if count := len(values); count > 0 || argVal.IsRequired {
+			if argVal.MinValues > 0 && count < argVal.MinValues {
+				args := []string{argVal.getRawValue(), strconv.Itoa(count), fmt.Sprintf("at least %v", argVal.MinValues)}
+				err := generateError(p.currentCmd, InvalidArgumentCount, args)
 
-			return matches, &err
-		}
-
-		// test the value against the validator func if any
-		for _, fn := range argVal.ValidatorFns {
-			if err := fn(input); err != nil {
-				args := []string{input, err.Error()}
-				err := generateError(p.currentCmd, InvalidArgumentValue, args)
+				return matches, &err
+			} else if argVal.MaxValues > 0 && count > argVal.MaxValues {
+				args := []string{argVal.getRawValue(), strconv.Itoa(count), fmt.Sprintf("at most %v", argVal.MaxValues)}
+				err := generateError(p.currentCmd, InvalidArgumentCount, args)
 
 				return matches, &err
 			}
 		}
 
-		// test against validator regex if any
-		if argVal.ValidatorRe != nil && !argVal.ValidatorRe.MatchString(input) {
-			args := []string{input, "failed to match value against validator regex"}
-			err := generateError(p.currentCmd, InvalidArgumentValue, args)
-
-			return matches, &err
+		for _, input := range values {
+			if err := p.validateArgValue(argVal, input); err != nil {
+				return matches, err
+			}
 		}
 
 		argCfg := argMatches{
-			rawValue:   input,
+			rawValue:   strings.Join(values, " "),
+			values:     values,
 			instanceOf: *argVal,
+			source:     source,
+		}
+
+		// keep the converted value for the typed getters
+		if len(values) == 1 && !argVal.IsVariadic && len(argVal.ValueDelimiter) == 0 {
+			argCfg.value, _ = convertValue(argVal.ArgType, values[0])
 		}
 
 		matches = append(matches, argCfg)
@@ -388,3 +755,58 @@ func (p *Parser) getArgMatches(list []*Argument, args []string) ([]argMatches, *
 
 	return matches, nil
 }
+
+// Checks that a value passed to a map option is of the form `key=value`, then tests the key against the valid keys of the option and the value against the validator function of its key
+func (p *Parser) validateMapEntry(opt *Option, entry string) *Error {
+	key, value, found := strings.Cut(entry, "=")
+	if !found || len(key) == 0 {
+		err := generateError(p.currentCmd, InvalidArgumentValue, []string{entry, fmt.Sprintf("The option: `%v` expects values of the form `key=value`", opt.LongVal)})
+		return &err
+	}
+
+	if len(opt.ValidKeys) > 0 && !containsString(opt.ValidKeys, key) {
+		err := generateError(p.currentCmd, InvalidArgumentValue, []string{entry, fmt.Sprintf("Expected one of the keys: `[%v]`, but instead found: `%v`", strings.Join(opt.ValidKeys, ", "), key)})
+		return &err
+	}
+
+	if fn, exists := opt.KeyValidatorFns[key]; exists {
+		if e := fn(value); e != nil {
+			err := generateError(p.currentCmd, InvalidArgumentValue, []string{entry, e.Error()})
+			return &err
+		}
+	}
+
+	return nil
+}
+
+// Tests a single value against the valid values, validator functions and validator regex of an argument
+func (p *Parser) validateArgValue(argVal *Argument, input string) *Error {
+	// test the value against default values if any
+	if len(argVal.ValidValues) > 0 && !argVal.testValue(input) {
+		args := []string{input}
+		args = append(args, argVal.ValidValues...)
+		err := generateError(p.currentCmd, InvalidArgumentValue, args)
+
+		return &err
+	}
+
+	// test the value against the validator func if any
+	for _, fn := range argVal.ValidatorFns {
+		if err := fn(input); err != nil {
+			args := []string{input, err.Error()}
+			err := generateError(p.currentCmd, InvalidArgumentValue, args)
+
+			return &err
+		}
+	}
+
+	// test against validator regex if any
+	if argVal.ValidatorRe != nil && !argVal.ValidatorRe.MatchString(input) {
+		args := []string{input, "failed to match value against validator regex"}
+		err := generateError(p.currentCmd, InvalidArgumentValue, args)
+
+		return &err
+	}
+
+	return nil
+}
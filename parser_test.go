@@ -1,11 +1,15 @@
 package gommander
 
 import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseBasic(t *testing.T) {
-	clearCache()
 	cmd := NewCommand("test").Flag("-v --version", "Version flag").Option("-p --port <port-no>", "Port option")
 	parser := NewParser(cmd)
 	matches, _ := parser.parse([]string{"-v", "-p", "90"})
@@ -16,7 +20,6 @@ func TestParseBasic(t *testing.T) {
 }
 
 func TestParseStandard(t *testing.T) {
-	clearCache()
 	app := NewCommand("echo")
 	app.SubCommand("first").Flag("-v --verbose", "Set verbose").Option("-n --name <value>", "Some name")
 
@@ -29,8 +32,6 @@ func TestParseStandard(t *testing.T) {
 }
 
 func TestParseComplex(t *testing.T) {
-	clearCache() // workaound for test mode
-
 	app := NewCommand("echo").Version("0.1.0").Help("A test CLI")
 
 	app.SubCommand("image").
@@ -69,7 +70,6 @@ func TestParseComplex(t *testing.T) {
 }
 
 func TestParseOptionSyntaxes(t *testing.T) {
-	clearCache()
 	app := NewCommand("basic").Option("-p --port <port-number>", "Port option")
 	parser := NewParser(app)
 
@@ -86,7 +86,6 @@ func TestParseOptionSyntaxes(t *testing.T) {
 }
 
 func _assertParserError(t *testing.T, app *Command, parserArgs, errorArgs []string, event Event, msg string) {
-	clearCache()
 	parser := NewParser(app)
 
 	_, err := parser.parse(parserArgs)
@@ -96,7 +95,6 @@ func _assertParserError(t *testing.T, app *Command, parserArgs, errorArgs []stri
 }
 
 func TestParserErrors(t *testing.T) {
-	clearCache()
 	app := NewCommand("echo").Version("0.1.0").Help("A test CLI")
 
 	app.SubCommand("image").
@@ -136,9 +134,9 @@ func TestParserErrors(t *testing.T) {
 
 	_assertParserError(t, app,
 		[]string{"i", "-p", "90"},
-		[]string{"<image-name>", "-p"},
+		[]string{"<image-name>"},
 		MissingRequiredArgument,
-		"Expected required arg but found option error detection failed",
+		"Missing required arg after an option not detected",
 	)
 
 	_assertParserError(t, app,
@@ -173,16 +171,16 @@ func TestParserErrors(t *testing.T) {
 
 	_assertParserError(t, app,
 		[]string{"basic", "eng", "-Vx"},
-		[]string{"x", "-Vx", ""},
+		[]string{"x", "-Vx", "2"},
 		UnknownOption,
 		"Unknown option(with posix flag syntax) error detection failed",
 	)
 
 	_assertParserError(t, app,
-		[]string{"i", "imageOne", "-p=90"},
-		[]string{},
+		[]string{"i", "imageOne", "-tx=90"},
+		[]string{"x", "-tx=90", "2"},
 		UnknownOption,
-		"Short option synatx not supported error detection failed",
+		"Unknown option in a short cluster error detection failed",
 	)
 
 	// test unresolved args errors
@@ -216,3 +214,546 @@ func BenchmarkParseEmpty(b *testing.B) {
 		parser.parse([]string{})
 	}
 }
+
+func TestParseEnvFallback(t *testing.T) {
+	os.Setenv("GOMMANDER_TEST_TOKEN", "secret")
+	os.Setenv("APP_LOG_LEVEL", "debug")
+	os.Setenv("GOMMANDER_TEST_FILE", "env.txt")
+	defer os.Unsetenv("GOMMANDER_TEST_TOKEN")
+	defer os.Unsetenv("APP_LOG_LEVEL")
+	defer os.Unsetenv("GOMMANDER_TEST_FILE")
+
+	app := App().EnvPrefix("APP")
+	app.AddOption(NewOption("token").Required(true).Argument("<token>").Env("GOMMANDER_TEST_TOKEN")).
+		AddOption(NewOption("log-level").Argument("<level>")).
+		AddOption(NewOption("region").Argument("<region>")).
+		AddArgument(NewArgument("<file>").Env("GOMMANDER_TEST_FILE"))
+
+	{
+		parser := NewParser(app)
+		matches, err := parser.parse([]string{})
+
+		assert(t, err == nil, "Required option satisfied by env var reported as missing")
+		token, _ := matches.GetOptionValue("token")
+		level, _ := matches.GetOptionValue("log-level")
+		file, _ := matches.GetArgValue("file")
+
+		assertEq(t, token, "secret", "Option value not read from env var")
+		assertEq(t, level, "debug", "Option value not read from the env var derived from the prefix")
+		assertEq(t, file, "env.txt", "Argument value not read from env var")
+		assert(t, !matches.ContainsOption("region"), "Option with unset env var matched")
+	}
+
+	{
+		parser := NewParser(app)
+		matches, _ := parser.parse([]string{"cli.txt", "--token", "other"})
+
+		token, _ := matches.GetOptionValue("token")
+		file, _ := matches.GetArgValue("file")
+
+		assertEq(t, token, "other", "Env var took precedence over the command line")
+		assertEq(t, file, "cli.txt", "Env var took precedence over the command line")
+	}
+
+	_, help := app.options[1].generate(app)
+	assertEq(t, help, " [env: APP_LOG_LEVEL]", "Env var not shown in option help")
+}
+
+func TestTypedGetters(t *testing.T) {
+	app := App()
+	app.Option("-p --port <int:port>", "The port").
+		Option("-r --retries <uint:retries>", "Retries").
+		Option("-t --timeout <duration:timeout>", "The timeout").
+		Option("-H --header <header>", "Headers").
+		Argument("<float:ratio>", "A ratio").
+		Argument("[bool:force]", "Force").
+		Argument("[name]", "A name")
+
+	parser := NewParser(app)
+	matches, perr := parser.parse([]string{"0.5", "true", "-p", "8080", "-r", "3", "-t", "1m30s", "-H", "a", "-H", "b"})
+	assert(t, perr == nil, "Unexpected error when parsing typed values")
+
+	port, err := matches.GetInt("port")
+	assert(t, err == nil, "Failed to get int value")
+	assertEq(t, port, 8080, "Wrong int value")
+
+	retries, _ := matches.GetUint("-r")
+	assertEq(t, retries, uint(3), "Wrong uint value")
+
+	timeout, _ := matches.GetDuration("timeout")
+	assertEq(t, timeout, 90*time.Second, "Wrong duration value")
+
+	ratio, _ := matches.GetFloat("ratio")
+	assertEq(t, ratio, 0.5, "Wrong float value")
+
+	force, _ := matches.GetBool("force")
+	assertEq(t, force, true, "Wrong bool value")
+
+	headers, _ := matches.GetStringSlice("header")
+	assertDeepEq(t, headers, []string{"a", "b"}, "Wrong string slice value")
+
+	generic, err := Get[int](matches, "--port")
+	assert(t, err == nil && generic == 8080, "Generic getter failed to get int value")
+
+	slice, _ := Get[[]string](matches, "port")
+	assertDeepEq(t, slice, []string{"8080"}, "Generic getter failed to get string slice")
+
+	_, err = matches.GetFloat("port")
+	assert(t, err != nil && strings.Contains(err.Error(), "declared as type: `int`"), "Type mismatch not reported")
+
+	_, err = Get[int](matches, "header")
+	assert(t, err != nil, "String value returned as int")
+
+	_, err = matches.GetInt("name")
+	assert(t, err != nil, "Value returned for missing argument")
+
+	_, err = matches.GetInt("unknown")
+	assert(t, err != nil, "Value returned for unknown option")
+}
+
+func TestParseVariadicValues(t *testing.T) {
+	app := App()
+	app.AddArgument(NewArgument("<int:ports...>").AtLeast(2).AtMost(3)).
+		AddOption(NewOption("name").AddArgument(NewArgument("<name>")))
+
+	{
+		parser := NewParser(app)
+		matches, err := parser.parse([]string{"80", "443", "--name", "web server"})
+
+		assert(t, err == nil, "Unexpected error when parsing variadic values")
+		assertDeepEq(t, matches.GetArgValues("ports"), []string{"80", "443"}, "Variadic values not stored as a slice")
+		v, _ := matches.GetArgValue("ports")
+		assertEq(t, v, "80 443", "Variadic values not joined in the raw value")
+	}
+
+	{
+		parser := NewParser(app)
+		_, err := parser.parse([]string{"80", "eighty"})
+		assert(t, err != nil && err.kind == InvalidArgumentValue, "Variadic values not validated separately")
+		assertDeepEq(t, err.args, []string{"eighty", "`eighty` is not a valid integer"}, "Wrong value reported as invalid")
+	}
+
+	{
+		parser := NewParser(app)
+		_, err := parser.parse([]string{"80"})
+		assert(t, err != nil && err.kind == InvalidArgumentCount, "Minimum number of values not enforced")
+		assertEq(t, err.exitCode, 80, "Wrong exit code for invalid argument count")
+	}
+
+	{
+		parser := NewParser(app)
+		_, err := parser.parse([]string{"80", "81", "82", "83"})
+		assert(t, err != nil && err.kind == InvalidArgumentCount, "Maximum number of values not enforced")
+		assertEq(t, err.args[2], "at most 3", "Wrong bound reported")
+	}
+
+	{
+		parser := NewParser(app)
+		_, err := parser.parse([]string{})
+		assert(t, err != nil && err.kind == MissingRequiredArgument, "Missing required variadic argument not reported")
+	}
+
+	{
+		cmd := App()
+		cmd.AddArgument(NewArgument("<files...>").ValidateWith([]string{"a b", "c"}))
+		parser := NewParser(cmd)
+		matches, err := parser.parse([]string{"a b", "c"})
+
+		assert(t, err == nil, "Values containing spaces rejected")
+		assertDeepEq(t, matches.GetArgValues("files"), []string{"a b", "c"}, "Values containing spaces merged")
+	}
+}
+
+func TestParsePrefixMatching(t *testing.T) {
+	app := App().Name("app").Set(AllowPrefixMatching, true)
+	app.SubCommand("install").
+		Alias("add").
+		Flag("--verbose", "Print more output").
+		Flag("--dry-run", "Print the changes without installing").
+		Option("--version-tag <tag>", "The tag to install").
+		Option("--verbosity <int:level>", "The verbosity level")
+	app.SubCommand("instance")
+	app.SubCommand("update")
+
+	parser := NewParser(app)
+	matches, err := parser.parse([]string{"upd"})
+	assert(t, err == nil, "Unambiguous subcommand prefix not matched")
+	assertEq(t, matches.GetMatchedCommand().GetName(), "update", "Wrong subcommand matched by prefix")
+
+	parser = NewParser(app)
+	matches, err = parser.parse([]string{"ad", "--dry", "--version-t", "v2", "--verbosi=3"})
+	assert(t, err == nil, "Unambiguous prefixes not matched")
+	assertEq(t, matches.GetMatchedCommand().GetName(), "install", "Aliases not matched by prefix")
+	assert(t, matches.ContainsFlag("dry-run"), "Long flag not matched by prefix")
+	tag, _ := matches.GetOptionValue("version-tag")
+	assertEq(t, tag, "v2", "Long option not matched by prefix")
+	level, _ := matches.GetOptionValue("verbosity")
+	assertEq(t, level, "3", "Long option syntax not matched by prefix")
+
+	_assertParserError(t, app,
+		[]string{"inst"},
+		[]string{"inst", "install", "instance"},
+		AmbiguousPrefix,
+		"Ambiguous subcommand prefix not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"install", "--verbos"},
+		[]string{"--verbos", "--verbose", "--verbosity"},
+		AmbiguousPrefix,
+		"Ambiguous long option prefix not reported",
+	)
+
+	app.Set(AllowPrefixMatching, false)
+	_assertParserError(t, app,
+		[]string{"upd"},
+		[]string{"upd"},
+		UnknownCommand,
+		"Prefixes matched without the setting",
+	)
+}
+
+func TestParseNegatableFlags(t *testing.T) {
+	app := App().Set(AllowPrefixMatching, true)
+	app.Flag("--[no-]color", "Colorize the output").Flag("-q --quiet", "Print less output")
+
+	cases := []struct {
+		args  []string
+		state FlagState
+	}{
+		{[]string{}, FlagNotGiven},
+		{[]string{"--color"}, FlagTrue},
+		{[]string{"--no-color"}, FlagFalse},
+		{[]string{"--color", "--no-color"}, FlagFalse},
+		{[]string{"--no-color", "--color"}, FlagTrue},
+		{[]string{"--no-c"}, FlagFalse},
+	}
+
+	for _, c := range cases {
+		parser := NewParser(app)
+		matches, err := parser.parse(c.args)
+		assert(t, err == nil, "Negatable flag not parsed: ", c.args)
+		assertEq(t, matches.GetFlagState("color"), c.state, "Wrong flag state for: ", c.args)
+		assertEq(t, matches.ContainsFlag("color"), c.state == FlagTrue, "Turned off flags reported as present: ", c.args)
+	}
+
+	_assertParserError(t, app,
+		[]string{"--no-quiet"},
+		[]string{"--no-quiet"},
+		UnknownOption,
+		"Flags that are not negatable turned off",
+	)
+}
+
+func TestParseCountedFlags(t *testing.T) {
+	app := NewCommand("app")
+	app.Flag("-v --verbose", "Print more output").
+		Flag("-q --quiet", "Print less output").
+		Flag("--[no-]color", "Colorize the output")
+
+	cases := []struct {
+		args    []string
+		verbose int
+		quiet   int
+	}{
+		{[]string{}, 0, 0},
+		{[]string{"-v"}, 1, 0},
+		{[]string{"-v", "-v", "--verbose"}, 3, 0},
+		{[]string{"-vvv"}, 3, 0},
+		{[]string{"-vqv", "-q"}, 2, 2},
+	}
+
+	for _, c := range cases {
+		parser := NewParser(app)
+		matches, err := parser.parse(c.args)
+		assert(t, err == nil, "Repeated flags not parsed: ", c.args)
+		assertEq(t, matches.GetFlagCount("verbose"), c.verbose, "Wrong count for: ", c.args)
+		assertEq(t, matches.GetFlagCount("-q"), c.quiet, "Wrong count for: ", c.args)
+	}
+
+	parser := NewParser(app)
+	matches, _ := parser.parse([]string{"--color", "--color", "--no-color", "--color"})
+	assertEq(t, matches.GetFlagCount("color"), 1, "Negated flag count not reset")
+}
+
+func TestParseOptionValueShapes(t *testing.T) {
+	app := NewCommand("app").
+		Option("--range <int:from> <int:to>", "A range of values").
+		AddOption(NewOption("tags").Short('t').Argument("<tag>").Delimiter(",")).
+		AddOption(NewOption("all").Help("An option without arguments")).
+		AddOption(
+			NewOption("define").
+				Short('D').
+				Map(true).
+				Delimiter(",").
+				ValidateKeys([]string{"env", "port"}).
+				KeyValidatorFunc("port", func(s string) error {
+					if _, err := strconv.Atoi(s); err != nil {
+						return errors.New("the port must be a number")
+					}
+					return nil
+				}),
+		)
+
+	parser := NewParser(app)
+	matches, err := parser.parse([]string{"--range", "1", "5", "--range", "7", "9", "-t", "a,b", "--tags=c", "-D", "env=prod", "-D", "port=80,env=dev"})
+	assert(t, err == nil, "Options with several values not parsed")
+	assertDeepEq(t, matches.GetOptionOccurrences("range"), [][]string{{"1", "5"}, {"7", "9"}}, "Option values not grouped by occurrence")
+	assertDeepEq(t, matches.GetAllOptionInstances("tags"), []string{"a", "b", "c"}, "Delimited option values not split")
+	assertDeepEq(t, matches.GetOptionMap("define"), map[string]string{"env": "dev", "port": "80"}, "Map option values not collected")
+
+	from, _ := matches.GetOptionValue("range")
+	assertEq(t, from, "1", "Value of the first argument not returned for an option with several arguments")
+	tags, _ := matches.GetOptionValue("tags")
+	assertEq(t, tags, "a b", "Delimited values of the first occurrence not joined")
+
+	parser = NewParser(app)
+	matches, err = parser.parse([]string{"--all"})
+	assert(t, err == nil, "Option without arguments not parsed: ", err)
+	_, e := matches.GetOptionValue("all")
+	assert(t, e != nil, "Value returned for an option without arguments")
+
+	parser = NewParser(app)
+	matches, err = parser.parse([]string{"--define=env=prod", "--tags=a=b"})
+	assert(t, err == nil, "Long option syntax with `=` in the value not parsed")
+	assertDeepEq(t, matches.GetOptionMap("define"), map[string]string{"env": "prod"}, "Value cut at the second `=` sign")
+	assertDeepEq(t, matches.GetAllOptionInstances("tags"), []string{"a=b"}, "Value cut at the second `=` sign")
+
+	parser = NewParser(app)
+	matches, err = parser.parse([]string{"--range", "3", "3", "-t", "3"})
+	assert(t, err == nil, "Repeated values not parsed: ", err)
+	assertDeepEq(t, matches.GetOptionOccurrences("range"), [][]string{{"3", "3"}}, "Repeated values of an option not consumed")
+	assertDeepEq(t, matches.GetAllOptionInstances("tags"), []string{"3"}, "Value of an option already consumed by another option")
+
+	_assertParserError(t, app,
+		[]string{"--range", "1", "x"},
+		[]string{"x", "`x` is not a valid integer"},
+		InvalidArgumentValue,
+		"Each argument of an option not validated",
+	)
+
+	_assertParserError(t, app,
+		[]string{"-D", "env"},
+		[]string{"env", "The option: `--define` expects values of the form `key=value`"},
+		InvalidArgumentValue,
+		"Map entries without a value not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"-D", "user=root"},
+		[]string{"user=root", "Expected one of the keys: `[env, port]`, but instead found: `user`"},
+		InvalidArgumentValue,
+		"Unknown map keys not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"-D", "port=http"},
+		[]string{"port=http", "the port must be a number"},
+		InvalidArgumentValue,
+		"Map values not validated per key",
+	)
+}
+
+func TestParseOptionalValues(t *testing.T) {
+	app := NewCommand("app").
+		AddOption(NewOption("color").Short('c').AddArgument(NewArgument("<when>").ValidateWith([]string{"always", "auto", "never"})).OptionalValue("always")).
+		Argument("[file]", "The file to print")
+	app.SubCommand("build")
+
+	parser := NewParser(app)
+	matches, err := parser.parse([]string{"--color", "build"})
+	assert(t, err == nil, "Option with an optional value not parsed")
+	val, _ := matches.Parent().GetOptionValue("color")
+	assertEq(t, val, "always", "Implicit value not used for an option passed without a value")
+	assertEq(t, matches.GetMatchedCommand().name, "build", "Subcommand consumed by an option with an optional value")
+
+	for _, args := range [][]string{{"--color=never"}, {"-cnever"}} {
+		parser := NewParser(app)
+		matches, err := parser.parse(args)
+		assert(t, err == nil, "Attached optional value not parsed: ", args)
+		val, _ := matches.GetOptionValue("color")
+		assertEq(t, val, "never", "Attached optional value not used")
+	}
+
+	parser = NewParser(app)
+	matches, err = parser.parse([]string{"--color", "x.txt"})
+	assert(t, err == nil, "Positional argument after an option with an optional value not parsed: ", err)
+	val, _ = matches.GetOptionValue("color")
+	file, _ := matches.GetArgValue("file")
+	assertEq(t, val, "always", "Detached value consumed by an option with an optional value")
+	assertEq(t, file, "x.txt", "Positional argument after an option with an optional value not matched")
+
+	_assertParserError(t, app,
+		[]string{"--color=sometimes"},
+		[]string{"sometimes", "always", "auto", "never"},
+		InvalidArgumentValue,
+		"Attached optional values not validated",
+	)
+}
+
+func TestParseShortClusters(t *testing.T) {
+	app := NewCommand("tar").
+		Flag("-x --extract", "Extract files from an archive").
+		Flag("-v --verbose", "List the files processed").
+		Option("-f --file <archive>", "The archive to use").
+		Option("-p --port <int:port>", "A port number").
+		Option("-n --offset <int:offset>", "An offset").
+		AddOption(NewOption("color").Short('c').Argument("<when>").OptionalValue("always"))
+
+	cases := []struct {
+		args   []string
+		option string
+		value  string
+	}{
+		{[]string{"-p8080"}, "port", "8080"},
+		{[]string{"-p=8080"}, "port", "8080"},
+		{[]string{"-xvf", "archive.tar"}, "file", "archive.tar"},
+		{[]string{"-xvfarchive.tar"}, "file", "archive.tar"},
+		{[]string{"-xvf=archive.tar"}, "file", "archive.tar"},
+		{[]string{"-xc"}, "color", "always"},
+		{[]string{"-xcnever"}, "color", "never"},
+		{[]string{"-xc="}, "color", "always"},
+		{[]string{"-f-"}, "file", "-"},
+		{[]string{"-xf-"}, "file", "-"},
+		{[]string{"-n-1"}, "offset", "-1"},
+	}
+
+	for _, c := range cases {
+		parser := NewParser(app)
+		matches, err := parser.parse(c.args)
+		assert(t, err == nil, "Short cluster not parsed: ", c.args)
+		val, _ := matches.GetOptionValue(c.option)
+		assertEq(t, val, c.value, "Wrong option value in short cluster: ", c.args)
+	}
+
+	parser := NewParser(app)
+	matches, _ := parser.parse([]string{"-xvvf", "archive.tar"})
+	assert(t, matches.ContainsFlag("extract"), "Flags before an option in a cluster not matched")
+	assertEq(t, matches.GetFlagCount("verbose"), 2, "Repeated flags in a cluster not counted")
+
+	_assertParserError(t, app,
+		[]string{"-xqf", "archive.tar"},
+		[]string{"q", "-xqf", "2"},
+		UnknownOption,
+		"Failing character in a short cluster not reported",
+	)
+
+	_assertParserError(t, app,
+		[]string{"-xf"},
+		[]string{"<archive>"},
+		MissingRequiredArgument,
+		"Option at the end of a cluster without a value not reported",
+	)
+
+	for _, args := range [][]string{{"-f="}, {"-xf="}, {"-xf=", "archive.tar"}} {
+		_assertParserError(t, app,
+			args,
+			[]string{"<archive>"},
+			MissingRequiredArgument,
+			"Empty value after `=` in a short cluster not reported",
+		)
+	}
+}
+
+func TestParseGlobalOptions(t *testing.T) {
+	app := App().Name("app").Set(OverrideAllDefaultListeners, true)
+	deploy := app.SubCommand("deploy")
+	deploy.SubCommand("prod")
+
+	// global flags and options added after the subcommands still apply to them
+	app.AddOption(NewOption("log-level").Short('l').Argument("<level>").Global(true)).
+		AddFlag(NewFlag("debug").Short('d').Global(true))
+
+	cases := [][]string{
+		{"app", "--log-level", "warn", "deploy", "prod"},
+		{"app", "deploy", "-l", "warn", "prod"},
+		{"app", "deploy", "prod", "--log-level=warn", "-d"},
+	}
+
+	for _, args := range cases {
+		level := ""
+		prod, _ := app.LookupCommand("deploy prod")
+		prod.Action(func(pm *ParserMatches) {
+			level, _ = pm.GetOptionValue("log-level")
+		})
+
+		err := app.ExecuteFrom(args)
+		assert(t, err == nil, "Global option not accepted: ", args, err)
+		assertEq(t, level, "warn", "Global option not readable from a descendant: ", args)
+	}
+
+	assertEq(t, len(deploy.GetOptions()), 1, "Global option not inherited by a subcommand")
+
+	deploy.Option("--log-level <level>", "Conflicts with the global option")
+	errs := app.Validate()
+	assertEq(t, len(errs), 1, "Conflict with a global option not reported")
+	assertEq(t, errs[0].Kind, ConflictingGlobalFlag, "Wrong kind for a conflict with a global option")
+}
+
+func TestParseCommandLevels(t *testing.T) {
+	app := NewCommand("app").
+		Option("--region <region>", "The region of the app").
+		Option("--profile <name>", "The profile to use").
+		AddOption(NewOption("log-level").Argument("<level>").Global(true))
+	deploy := app.SubCommand("deploy").Option("--region <region>", "The region to deploy to")
+	other := app.SubCommand("status")
+
+	parser := NewParser(app)
+	matches, err := parser.parse([]string{"--region", "eu", "--profile", "dev", "deploy", "--region", "us", "--log-level", "warn"})
+	assert(t, err == nil, "Options on several command levels not parsed: ", err)
+
+	region, _ := matches.GetOptionValue("region")
+	assertEq(t, region, "us", "Option of the matched command mixed with the option of its parent")
+	assertEq(t, len(matches.GetAllOptionInstances("region")), 1, "Values of an option defined on several levels not kept apart")
+
+	parent := matches.Parent()
+	assert(t, parent != nil, "Matches of the parent command not kept")
+	assertEq(t, parent.GetMatchedCommand(), app, "Wrong command for the parent matches")
+	region, _ = parent.GetOptionValue("region")
+	assertEq(t, region, "eu", "Option passed before the subcommand not kept on the parent level")
+	assert(t, parent.Parent() == nil, "Matches of the root command have a parent")
+
+	_, e := matches.GetOptionValue("profile")
+	assert(t, e != nil, "Non-global option of a parent readable from the matched command")
+	profile, _ := parent.GetOptionValue("profile")
+	assertEq(t, profile, "dev", "Option of a parent not readable from its own matches")
+
+	level, _ := parent.GetOptionValue("log-level")
+	assertEq(t, level, "warn", "Global option passed after the subcommand not recorded on the level that defines it")
+	level, _ = matches.GetOptionValue("log-level")
+	assertEq(t, level, "warn", "Global option of a parent not readable from the matched command")
+
+	assertEq(t, matches.ForCommand(app), parent, "Matches of an ancestor not found by command")
+	assertEq(t, matches.ForCommand(deploy), matches, "Matches of the matched command not found by command")
+	assert(t, matches.ForCommand(other) == nil, "Matches found for a command that was not matched")
+
+	parser = NewParser(app)
+	matches, _ = parser.parse([]string{"--help", "deploy"})
+	assert(t, matches.helpRequested(), "Help flag passed before the subcommand not detected")
+}
+
+func TestParseAncestorFallbacks(t *testing.T) {
+	os.Setenv("APP_PROFILE", "staging")
+	defer os.Unsetenv("APP_PROFILE")
+
+	app := NewCommand("app").
+		AddOption(NewOption("profile").Argument("<name>").Env("APP_PROFILE")).
+		RequiredOption("--region <region>", "The region of the app")
+	app.SubCommand("deploy")
+
+	parser := NewParser(app)
+	matches, err := parser.parse([]string{"--region", "eu", "deploy"})
+	assert(t, err == nil, "Options of the root command not filled in when a subcommand is matched: ", err)
+
+	profile, _ := matches.Parent().GetOptionValue("profile")
+	assertEq(t, profile, "staging", "Environment fallback of a root option not applied when a subcommand is matched")
+	src, _ := matches.Parent().GetValueSource("profile")
+	assertEq(t, src, SourceEnv, "Wrong source for the environment fallback of a root option")
+
+	_assertParserError(t, app,
+		[]string{"deploy"},
+		[]string{"--region"},
+		MissingRequiredOption,
+		"Required option of the root command not enforced when a subcommand is matched",
+	)
+}
@@ -23,4 +23,51 @@ const (
 	AllowNegativeNumbers
 	// A setting to enable or disable color formatting and printing
 	DisableColor
+	// Configures whether to include the hidden `completion <shell>` subcommand for generating shell completion scripts, false by default
+	IncludeCompletionSubcommand
+	// Configures whether to include the hidden `man [dir]` subcommand for generating man pages, false by default
+	IncludeManSubcommand
+	// Allows subcommands, aliases and long flags and options to be matched by an unambiguous prefix, i.e. `app inst` resolves to `app install`, false by default
+	AllowPrefixMatching
 )
+
+var settingsSlice = []Setting{
+	ShowCommandAliases, ShowHelpOnAllErrors,
+	IncludeHelpSubcommand, OverrideAllDefaultListeners,
+	DisableVersionFlag, IgnoreAllErrors,
+	SortItemsAlphabetically, AllowNegativeNumbers,
+	DisableColor, IncludeCompletionSubcommand,
+	IncludeManSubcommand, AllowPrefixMatching,
+}
+
+var settingNames = map[Setting]string{
+	ShowCommandAliases:          "ShowCommandAliases",
+	ShowHelpOnAllErrors:         "ShowHelpOnAllErrors",
+	IncludeHelpSubcommand:       "IncludeHelpSubcommand",
+	OverrideAllDefaultListeners: "OverrideAllDefaultListeners",
+	DisableVersionFlag:          "DisableVersionFlag",
+	IgnoreAllErrors:             "IgnoreAllErrors",
+	SortItemsAlphabetically:     "SortItemsAlphabetically",
+	AllowNegativeNumbers:        "AllowNegativeNumbers",
+	DisableColor:                "DisableColor",
+	IncludeCompletionSubcommand: "IncludeCompletionSubcommand",
+	IncludeManSubcommand:        "IncludeManSubcommand",
+	AllowPrefixMatching:         "AllowPrefixMatching",
+}
+
+// Returns the name of the setting, i.e. `ShowCommandAliases`
+func (s Setting) String() string {
+	if name, exists := settingNames[s]; exists {
+		return name
+	}
+	return "UnknownSetting"
+}
+
+func settingFromString(val string) (Setting, bool) {
+	for s, name := range settingNames {
+		if name == val {
+			return s, true
+		}
+	}
+	return 0, false
+}
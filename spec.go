@@ -0,0 +1,324 @@
+package gommander
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// The version of the spec document format. It is incremented whenever the format changes in a backwards-incompatible way
+const SpecVersion = 1
+
+// A machine-readable description of a command tree that can be serialized to JSON and used to build a command tree
+type Spec struct {
+	SpecVersion int         `json:"spec_version"`
+	Program     CommandSpec `json:"program"`
+}
+
+type CommandSpec struct {
+	Name        string              `json:"name"`
+	Aliases     []string            `json:"aliases,omitempty"`
+	Help        string              `json:"help,omitempty"`
+	Discussion  string              `json:"discussion,omitempty"`
+	Author      string              `json:"author,omitempty"`
+	Version     string              `json:"version,omitempty"`
+	Usage       string              `json:"usage,omitempty"`
+	Hidden      bool                `json:"hidden,omitempty"`
+	EnvPrefix   string              `json:"env_prefix,omitempty"`
+	ConfigFile  string              `json:"config_file,omitempty"`
+	Settings    []string            `json:"settings,omitempty"`
+	Arguments   []ArgumentSpec      `json:"arguments,omitempty"`
+	Flags       []FlagSpec          `json:"flags,omitempty"`
+	Options     []OptionSpec        `json:"options,omitempty"`
+	SubCommands []CommandSpec       `json:"subcommands,omitempty"`
+	Groups      map[string][]string `json:"groups,omitempty"`
+	// The constraints declared on the flags and options of the command
+	OptionGroups []OptionGroupSpec `json:"option_groups,omitempty"`
+}
+
+type ArgumentSpec struct {
+	Name        string   `json:"name"`
+	Display     string   `json:"display,omitempty"`
+	Help        string   `json:"help,omitempty"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Variadic    bool     `json:"variadic"`
+	MinValues   int      `json:"min_values,omitempty"`
+	MaxValues   int      `json:"max_values,omitempty"`
+	ValidValues []string `json:"valid_values,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Env         string   `json:"env,omitempty"`
+	Delimiter   string   `json:"delimiter,omitempty"`
+}
+
+type FlagSpec struct {
+	Name      string `json:"name"`
+	Short     string `json:"short,omitempty"`
+	Long      string `json:"long,omitempty"`
+	Help      string `json:"help,omitempty"`
+	Global    bool   `json:"global,omitempty"`
+	Negatable bool   `json:"negatable,omitempty"`
+}
+
+type OptionSpec struct {
+	Name     string `json:"name"`
+	Short    string `json:"short,omitempty"`
+	Long     string `json:"long,omitempty"`
+	Help     string `json:"help,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Env      string `json:"env,omitempty"`
+	// The arguments of the option, several for options taking more than one value per occurrence
+	Arguments     []ArgumentSpec `json:"arguments,omitempty"`
+	Map           bool           `json:"map,omitempty"`
+	ValidKeys     []string       `json:"valid_keys,omitempty"`
+	OptionalValue bool           `json:"optional_value,omitempty"`
+	ImplicitValue string         `json:"implicit_value,omitempty"`
+	Global        bool           `json:"global,omitempty"`
+}
+
+type OptionGroupSpec struct {
+	// One of `exclusive`, `required_together`, `one_of_required` or `required_if`
+	Kind    string   `json:"kind"`
+	Members []string `json:"members"`
+	// The option and value that make the members of a `required_if` group required
+	Option string `json:"option,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+/****************************** Spec export ****************************/
+
+// Returns the spec of the command tree. Flags, options and subcommands added by the package itself, such as the help and version flags, are left out since they are added again when the tree is built from the spec
+func (c *Command) ExportSpec() Spec {
+	return Spec{SpecVersion: SpecVersion, Program: c.commandSpec()}
+}
+
+// Writes the spec of the command tree to the provided writer as an indented JSON document
+func (c *Command) WriteSpec(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c.ExportSpec())
+}
+
+func (c *Command) commandSpec() CommandSpec {
+	spec := CommandSpec{
+		Name:       c.name,
+		Aliases:    c.aliases,
+		Help:       c.help,
+		Discussion: c.discussion,
+		Author:     c.author,
+		Version:    c.version,
+		Usage:      c.customUsageStr,
+		Hidden:     c.hidden,
+		EnvPrefix:  c.envPrefix,
+		ConfigFile: c.configName,
+	}
+
+	for _, s := range settingsSlice {
+		if c.settings[s] {
+			spec.Settings = append(spec.Settings, s.String())
+		}
+	}
+
+	for _, a := range c.arguments {
+		spec.Arguments = append(spec.Arguments, a.spec())
+	}
+
+	for _, f := range c.flags {
+		if *f == *helpFlag() || *f == *versionFlag() {
+			continue
+		}
+		spec.Flags = append(spec.Flags, FlagSpec{Name: f.Name, Short: f.ShortVal, Long: f.LongVal, Help: f.HelpStr, Global: f.IsGlobal, Negatable: f.IsNegatable})
+	}
+
+	for _, o := range c.options {
+		if len(c.configName) > 0 && o.LongVal == "--config" {
+			continue
+		}
+		opt := OptionSpec{Name: o.Name, Short: o.ShortVal, Long: o.LongVal, Help: o.HelpStr, Required: o.IsRequired, Env: o.EnvVar, Map: o.IsMap, ValidKeys: o.ValidKeys, OptionalValue: o.IsValueOptional, ImplicitValue: o.ImplicitValue, Global: o.IsGlobal}
+		for _, a := range o.arguments() {
+			opt.Arguments = append(opt.Arguments, a.spec())
+		}
+		spec.Options = append(spec.Options, opt)
+	}
+
+	for _, g := range c.optionGroups {
+		spec.OptionGroups = append(spec.OptionGroups, OptionGroupSpec{Kind: g.kind.String(), Members: g.members, Option: g.condOption, Value: g.condValue})
+	}
+
+	for _, s := range c.subCommands {
+		if !s.isBuiltin {
+			spec.SubCommands = append(spec.SubCommands, s.commandSpec())
+		}
+	}
+
+	for name, cmds := range c.subCmdGroups {
+		if spec.Groups == nil {
+			spec.Groups = make(map[string][]string)
+		}
+		for _, s := range cmds {
+			spec.Groups[name] = append(spec.Groups[name], s.name)
+		}
+	}
+
+	return spec
+}
+
+func (a *Argument) spec() ArgumentSpec {
+	return ArgumentSpec{
+		Name:        a.Name,
+		Display:     a.RawValue,
+		Help:        a.HelpStr,
+		Type:        string(a.ArgType),
+		Required:    a.IsRequired,
+		Variadic:    a.IsVariadic,
+		MinValues:   a.MinValues,
+		MaxValues:   a.MaxValues,
+		ValidValues: a.ValidValues,
+		Default:     a.DefaultValue,
+		Env:         a.EnvVar,
+		Delimiter:   a.ValueDelimiter,
+	}
+}
+
+/****************************** Spec import ****************************/
+
+// Reads a spec from a JSON document. An error is returned if the document is invalid or was written using an unsupported version of the spec format
+func ReadSpec(r io.Reader) (Spec, error) {
+	var spec Spec
+	if err := json.NewDecoder(r).Decode(&spec); err != nil {
+		return spec, fmt.Errorf("invalid spec document: %v", err)
+	}
+	if spec.SpecVersion != SpecVersion {
+		return spec, fmt.Errorf("unsupported spec version: `%v`, expected version: `%v`", spec.SpecVersion, SpecVersion)
+	}
+	return spec, nil
+}
+
+// Builds a command tree from a spec. Actions can then be attached to the commands in the tree via the `.ActionAt()` method. Invalid definitions in the spec, such as unknown settings or argument types, are reported when the program is parsed
+func AppFromSpec(spec Spec) *Command {
+	app := App()
+	app.fromSpec(spec.Program)
+	return app
+}
+
+func (c *Command) fromSpec(spec CommandSpec) {
+	if c.isRoot {
+		c.Name(spec.Name)
+	}
+	c.Help(spec.Help).Discussion(spec.Discussion).Author(spec.Author).Hidden(spec.Hidden)
+	c.version = spec.Version
+	c.customUsageStr = spec.Usage
+	c.envPrefix = spec.EnvPrefix
+	c.aliases = append(c.aliases, spec.Aliases...)
+
+	for _, name := range spec.Settings {
+		if s, exists := settingFromString(name); exists {
+			c.Set(s, true)
+		} else {
+			c.definitionErrs = append(c.definitionErrs, newDefinitionError(UnknownReference, "unknown setting: `%v` on command: `%v`", name, spec.Name))
+		}
+	}
+
+	if len(spec.ConfigFile) > 0 {
+		c.ConfigFile(spec.ConfigFile)
+	}
+
+	for _, a := range spec.Arguments {
+		c.AddArgument(argumentFromSpec(a))
+	}
+
+	for _, f := range spec.Flags {
+		c.AddFlag(&Flag{Name: f.Name, ShortVal: f.Short, LongVal: f.Long, HelpStr: f.Help, IsGlobal: f.Global, IsNegatable: f.Negatable})
+	}
+
+	for _, o := range spec.Options {
+		opt := &Option{Name: o.Name, ShortVal: o.Short, LongVal: o.Long, HelpStr: o.Help, IsRequired: o.Required, EnvVar: o.Env, IsMap: o.Map, ValidKeys: o.ValidKeys, IsValueOptional: o.OptionalValue, ImplicitValue: o.ImplicitValue, IsGlobal: o.Global}
+		for _, a := range o.Arguments {
+			opt.AddArgument(argumentFromSpec(a))
+		}
+		c.AddOption(opt)
+	}
+
+	for _, g := range spec.OptionGroups {
+		kind, exists := optionGroupKindFromString(g.Kind)
+		if !exists {
+			c.definitionErrs = append(c.definitionErrs, newDefinitionError(UnknownReference, "unknown option group kind: `%v` on command: `%v`", g.Kind, spec.Name))
+			continue
+		}
+		c.optionGroups = append(c.optionGroups, &optionGroup{cmd: c, kind: kind, members: g.Members, condOption: g.Option, condValue: g.Value})
+	}
+
+	for _, s := range spec.SubCommands {
+		c.SubCommand(s.Name).fromSpec(s)
+	}
+
+	groups := []string{}
+	for name := range spec.Groups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	for _, name := range groups {
+		for _, s := range spec.Groups[name] {
+			if subCmd, err := c.findSubcommand(s); err == nil {
+				c.SubCommandGroup(name, []*Command{subCmd})
+			} else {
+				c.definitionErrs = append(c.definitionErrs, newDefinitionError(UnknownReference, "unknown subcommand: `%v` in group: `%v`", s, name))
+			}
+		}
+	}
+}
+
+func argumentFromSpec(spec ArgumentSpec) *Argument {
+	arg := NewArgument(spec.Name).
+		Help(spec.Help).
+		Required(spec.Required).
+		Variadic(spec.Variadic).
+		AtLeast(spec.MinValues).
+		AtMost(spec.MaxValues).
+		Env(spec.Env)
+
+	if len(spec.Display) > 0 {
+		arg.DisplayAs(spec.Display)
+	}
+	if len(spec.Type) > 0 && argumentType(spec.Type) != str {
+		arg.Type(argumentType(spec.Type))
+	}
+	if len(spec.ValidValues) > 0 {
+		arg.ValidateWith(spec.ValidValues)
+	}
+	if len(spec.Default) > 0 {
+		arg.Default(spec.Default)
+	}
+	if len(spec.Delimiter) > 0 {
+		arg.Delimiter(spec.Delimiter)
+	}
+
+	return arg
+}
+
+// Returns the command found at the given path of subcommand names or aliases, i.e. `remote add`. The path is relative to the command on which the method is invoked
+func (c *Command) LookupCommand(path string) (*Command, error) {
+	cmd := c
+	for _, name := range strings.Fields(path) {
+		subCmd, err := cmd.findSubcommand(name)
+		if err != nil {
+			return nil, fmt.Errorf("no such command: `%v`", path)
+		}
+		cmd = subCmd
+	}
+	return cmd, nil
+}
+
+// Sets the action of the command found at the given path, i.e. `remote add`. It is mostly useful for attaching actions to command trees built from a spec. Unknown paths are reported as definition errors when the program is parsed
+func (c *Command) ActionAt(path string, cb CommandCallback) *Command {
+	cmd, err := c.LookupCommand(path)
+	if err != nil {
+		c.definitionErrs = append(c.definitionErrs, DefinitionError{Kind: UnknownReference, Message: err.Error()})
+		return c
+	}
+	cmd.Action(cb)
+	return c
+}
@@ -0,0 +1,95 @@
+package gommander
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpecExport(t *testing.T) {
+	app := App().Name("git").Version("2.0.0").Author("vndaba").Help("A distributed version control system").EnvPrefix("GIT")
+	app.Set(IncludeHelpSubcommand, true).Set(ShowCommandAliases, true)
+	app.AddFlag(NewFlag("verbose").Short('V').Help("Print more output").Global(true))
+
+	remote := app.SubCommand("remote").Alias("r").Help("Manage tracked repositories")
+	remote.SubCommand("add").
+		Help("Add a new remote").
+		Argument("<name>", "The name of the remote").
+		AddArgument(NewArgument("[url]").Help("The url of the remote").Default("origin").Env("GIT_URL")).
+		AddOption(NewOption("track").Short('t').Help("Track a branch").AddArgument(NewArgument("<branch...>").AtLeast(1))).
+		AddOption(NewOption("mirror").Help("Mirror mode").Required(true).AddArgument(NewArgument("<mode>").ValidateWith([]string{"fetch", "push"}).Default("fetch")))
+	app.SubCommand("clone").
+		Argument("<int:depth>", "The depth").
+		Hidden(true)
+	app.SubCommandGroup("Collaboration", []*Command{remote})
+	app._init() // builtin subcommands are not exported
+
+	var doc bytes.Buffer
+	if err := app.WriteSpec(&doc); err != nil {
+		t.Fatal(err)
+	}
+	_assertGolden(t, filepath.Join("testdata", "spec", "git.json.golden"), doc.String())
+}
+
+// The round trip and action tests build their trees from the exported golden spec
+func TestSpecRoundTrip(t *testing.T) {
+	first, _ := os.ReadFile(filepath.Join("testdata", "spec", "git.json.golden"))
+	spec, err := ReadSpec(bytes.NewReader(first))
+	assert(t, err == nil, "Failed to read spec: ", err)
+
+	var second bytes.Buffer
+	app := AppFromSpec(spec)
+	app.WriteSpec(&second)
+	assertEq(t, second.String(), string(first), "Spec changed after a round trip")
+
+	assertEq(t, len(app.getDefinitionErrors()), 0, "Spec built tree with definition errors")
+	add, _ := app.LookupCommand("r add")
+	assertEq(t, add.GetUsageStr(), "git remote add", "Wrong usage string for subcommand built from spec")
+	assertEq(t, add.GetFlags()[1].Name, "verbose", "Global flag not propagated to subcommand built from spec")
+}
+
+func TestSpecActions(t *testing.T) {
+	doc, _ := os.ReadFile(filepath.Join("testdata", "spec", "git.json.golden"))
+	spec, _ := ReadSpec(bytes.NewReader(doc))
+
+	called := ""
+	app := AppFromSpec(spec).
+		Set(OverrideAllDefaultListeners, true).
+		ActionAt("remote add", func(pm *ParserMatches) {
+			name, _ := pm.GetArgValue("name")
+			called = name
+		})
+
+	err := app.ExecuteFrom([]string{"git", "remote", "add", "upstream", "--mirror", "push"})
+	assert(t, err == nil, "Unexpected error when executing tree built from spec")
+	assertEq(t, called, "upstream", "Action attached by path not invoked")
+
+	err = app.ExecuteFrom([]string{"git", "remote", "add", "upstream", "--mirror", "pull"})
+	assert(t, err != nil, "Valid values not restored from spec")
+
+	app.ActionAt("remote delete", func(pm *ParserMatches) {})
+	err = app.ExecuteFrom([]string{"git", "remote"})
+	e, ok := err.(*Error)
+	assert(t, ok && e.GetKind() == InvalidDefinition, "Unknown command path not reported")
+}
+
+func TestSpecErrors(t *testing.T) {
+	_, err := ReadSpec(strings.NewReader(`{"spec_version": 2, "program": {"name": "app"}}`))
+	assert(t, err != nil && strings.Contains(err.Error(), "unsupported spec version"), "Unsupported spec version accepted")
+
+	_, err = ReadSpec(strings.NewReader(`{"spec_version": `))
+	assert(t, err != nil, "Invalid spec document accepted")
+
+	spec, _ := ReadSpec(strings.NewReader(`{
+		"spec_version": 1,
+		"program": {
+			"name": "app",
+			"settings": ["ShowColors"],
+			"arguments": [{"name": "count", "type": "number", "required": true, "variadic": false}]
+		}
+	}`))
+	errs := AppFromSpec(spec).getDefinitionErrors()
+	assertEq(t, len(errs), 2, "Invalid definitions in spec not reported")
+}
@@ -0,0 +1,175 @@
+package gommander
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultSuggestionDistance = 2
+	defaultMaxSuggestions     = 3
+)
+
+// Sets the maximum edit distance between an unknown subcommand, flag or option and a known one for the latter to be suggested, 2 by default. Setting it to 0 disables suggestions. Only the value of the root command is used
+func (c *Command) SuggestionDistance(val int) *Command {
+	c.suggestionDistance = val
+	return c
+}
+
+// Sets the maximum number of suggestions offered for an unknown subcommand, flag or option, 3 by default. Only the value of the root command is used
+func (c *Command) MaxSuggestions(val int) *Command {
+	c.maxSuggestions = val
+	return c
+}
+
+// Returns the names and aliases of the visible subcommands closest to the provided value
+func (c *Command) suggestSubCmd(val string) []string {
+	candidates := []string{}
+	for _, sc := range c.visibleSubCommands() {
+		candidates = append(candidates, sc.name)
+		candidates = append(candidates, sc.aliases...)
+	}
+	return c.suggest(val, candidates)
+}
+
+// Returns the long values of the flags and options of the command closest to the provided value. Short values are left out since any two of them are always within a small distance of each other
+func (c *Command) suggestSwitch(val string) []string {
+	if !strings.HasPrefix(val, "--") {
+		return []string{}
+	}
+
+	candidates := []string{}
+	for _, f := range c.allFlags() {
+		candidates = append(candidates, f.LongVal, f.negatedLongVal())
+	}
+	for _, o := range c.allOptions() {
+		candidates = append(candidates, o.LongVal)
+	}
+	return c.suggest(val, candidates)
+}
+
+// Looks for an unknown flag or option on the parent, sibling and child commands of the command. It returns a hint of the form "`--force` is an option of `app push`, not `app pull`", or an empty string if the value is not defined on any of them
+func (c *Command) locateSwitch(val string) string {
+	related := []*Command{}
+	if c.parent != nil {
+		related = append(related, c.parent)
+		related = append(related, c.parent.visibleSubCommands()...)
+	}
+	related = append(related, c.visibleSubCommands()...)
+
+	for _, cmd := range related {
+		if cmd == c {
+			continue
+		}
+
+		kind := ""
+		if _, err := cmd.findOption(val); err == nil {
+			kind = "an option"
+		}
+		for _, f := range cmd.flags {
+			if f.ShortVal == val || f.LongVal == val || f.negatedLongVal() == val {
+				kind = "a flag"
+			}
+		}
+		if len(kind) > 0 {
+			return fmt.Sprintf("`%v` is %v of `%v`, not `%v`", val, kind, strings.Join(cmd.commandPath(), " "), strings.Join(c.commandPath(), " "))
+		}
+	}
+	return ""
+}
+
+// Returns a hint for an unknown flag or option, pointing at the related command that defines it or else suggesting the closest flags and options of the command
+func (c *Command) switchHint(val string) string {
+	if hint := c.locateSwitch(val); len(hint) > 0 {
+		return " " + hint
+	}
+	if suggestions := c.suggestSwitch(val); len(suggestions) > 0 {
+		return " " + didYouMean(suggestions)
+	}
+	return ""
+}
+
+// Returns the candidates within the configured edit distance of the value, closest first and capped at the configured number of suggestions
+func (c *Command) suggest(val string, candidates []string) []string {
+	app := c._getAppRef()
+	maxDistance, maxCount := app.suggestionDistance, app.maxSuggestions
+
+	type match struct {
+		value    string
+		distance int
+	}
+
+	matches := []match{}
+	seen := make(map[string]bool)
+	for _, cand := range candidates {
+		if len(cand) == 0 || seen[cand] {
+			continue
+		}
+		seen[cand] = true
+		if d := editDistance(val, cand); d <= maxDistance {
+			matches = append(matches, match{cand, d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].value < matches[j].value
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(matches) && i < maxCount; i++ {
+		suggestions = append(suggestions, matches[i].value)
+	}
+	return suggestions
+}
+
+// Computes the Damerau-Levenshtein distance between two values, restricted to the optimal string alignment where no substring is edited more than once. Insertions, deletions, substitutions and transpositions of adjacent characters each count as a single edit
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
+
+func minInt(vals ...int) int {
+	m := vals[0]
+	for _, v := range vals[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// Formats suggestions as "Did you mean `a` or `b`?"
+func didYouMean(suggestions []string) string {
+	quoted := []string{}
+	for _, s := range suggestions {
+		quoted = append(quoted, fmt.Sprintf("`%v`", s))
+	}
+	return fmt.Sprintf("Did you mean %v?", strings.Join(quoted, " or "))
+}
@@ -0,0 +1,61 @@
+package gommander
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	assertEq(t, editDistance("push", "push"), 0, "Equal values have a non-zero distance")
+	assertEq(t, editDistance("psuh", "push"), 1, "Transpositions not counted as a single edit")
+	assertEq(t, editDistance("pul", "pull"), 1, "Insertions counted incorrectly")
+	assertEq(t, editDistance("commit", "cmomti"), 2, "Multiple transpositions counted incorrectly")
+	assertEq(t, editDistance("", "abc"), 3, "Distance from an empty value counted incorrectly")
+}
+
+func TestSubcommandSuggestions(t *testing.T) {
+	app := App().Name("app")
+	app.SubCommand("push").Alias("publish")
+	app.SubCommand("pull")
+	app.SubCommand("status")
+
+	assertDeepEq(t, app.suggestSubCmd("psuh"), []string{"push"}, "Transposed subcommand not suggested")
+	assertDeepEq(t, app.suggestSubCmd("pus"), []string{"push", "pull"}, "Suggestions not ordered by distance")
+	assertDeepEq(t, app.suggestSubCmd("publsih"), []string{"publish"}, "Aliases not suggested")
+	assertEq(t, len(app.suggestSubCmd("deploy")), 0, "Distant subcommand suggested")
+
+	app.MaxSuggestions(1)
+	assertDeepEq(t, app.suggestSubCmd("pus"), []string{"push"}, "Number of suggestions not capped")
+
+	app.SuggestionDistance(0)
+	assertEq(t, len(app.suggestSubCmd("psuh")), 0, "Suggestions not disabled")
+
+	// trees rooted with NewCommand use the same defaults
+	cmd := NewCommand("app")
+	cmd.SubCommand("push")
+	assertDeepEq(t, cmd.suggestSubCmd("psuh"), []string{"push"}, "No suggestions for a tree rooted with NewCommand")
+}
+
+func TestSwitchSuggestions(t *testing.T) {
+	app := App().Name("app").Set(OverrideAllDefaultListeners, true)
+	app.SubCommand("push").Flag("-f --force", "Force the push")
+	app.SubCommand("pull").Option("--rebase <bool:val>", "Rebase when pulling")
+	app.SubCommand("status")
+
+	err := app.ExecuteFrom([]string{"app", "push", "--forse"})
+	e := err.(*Error)
+	assertEq(t, e.GetKind(), UnknownOption, "Wrong error for unknown flag")
+	assert(t, strings.HasSuffix(e.context, "Did you mean `--force`?"), "Unknown flag without suggestions: ", e.context)
+
+	err = app.ExecuteFrom([]string{"app", "pull", "--force"})
+	e = err.(*Error)
+	assert(t, strings.HasSuffix(e.context, "`--force` is a flag of `app push`, not `app pull`"), "Flag of sibling command not located: ", e.context)
+
+	err = app.ExecuteFrom([]string{"app", "push", "--rebase=true"})
+	e = err.(*Error)
+	assert(t, strings.HasSuffix(e.context, "`--rebase` is an option of `app pull`, not `app push`"), "Option of sibling command not located: ", e.context)
+
+	err = app.ExecuteFrom([]string{"app", "stats"})
+	e = err.(*Error)
+	assert(t, strings.HasSuffix(e.context, "Did you mean `status`?"), "Unknown subcommand without suggestions: ", e.context)
+}
@@ -0,0 +1,218 @@
+package gommander
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The kind of mistake found in the definition of a command tree
+type DefinitionErrorKind byte
+
+const (
+	// Flags, options, arguments or subcommands that share a name or value on the same command
+	DuplicateDefinition DefinitionErrorKind = iota
+	// A global flag or option whose short or long value is also used by a flag or option on one of the descendants of its command
+	ConflictingGlobalFlag
+	// A short value that is not a dash followed by a single character, or a long value that does not start with two dashes
+	InvalidSwitchValue
+	// An unknown type prefix in an argument, i.e. `<fake:name>`
+	InvalidArgumentType
+	// A default value that is rejected by the valid values or validator functions of its argument
+	InvalidDefaultValue
+	// A struct field that cannot be bound to the command tree
+	InvalidBinding
+	// A setting, subcommand group member, option group member or command path that does not exist
+	UnknownReference
+)
+
+// A structured description of a mistake in the definition of a command tree, as returned by the `.Validate()` method
+type DefinitionError struct {
+	Kind DefinitionErrorKind
+	// The path of the command on which the mistake was found, i.e. `git remote add`
+	Command string
+	Message string
+}
+
+func (e DefinitionError) Error() string {
+	return e.Message
+}
+
+func newDefinitionError(kind DefinitionErrorKind, format string, args ...interface{}) DefinitionError {
+	return DefinitionError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// Checks the definition of the command and all of its subcommands and returns every problem found at once, such as conflicting flags and options, malformed short values, unknown argument types and invalid default values.
+// It is invoked automatically before a program is parsed, and the problems are reported through the `InvalidDefinition` event. Use `.ValidateForTest()` to catch a broken command tree from a test even when the program's errors are ignored
+func (c *Command) Validate() []DefinitionError {
+	errs := []DefinitionError{}
+	path := strings.Join(c.commandPath(), " ")
+
+	record := func(err DefinitionError) {
+		if len(err.Command) == 0 {
+			err.Command = path
+		}
+		errs = append(errs, err)
+	}
+
+	for _, e := range c.recordedErrors() {
+		if err, ok := e.(DefinitionError); ok {
+			record(err)
+		} else {
+			record(DefinitionError{Kind: DuplicateDefinition, Message: e.Error()})
+		}
+	}
+
+	for _, f := range c.flags {
+		for _, err := range checkSwitchValues("flag", f.ShortVal, f.LongVal, c.name) {
+			record(err)
+		}
+	}
+	for _, o := range c.options {
+		for _, err := range checkSwitchValues("option", o.ShortVal, o.LongVal, c.name) {
+			record(err)
+		}
+	}
+
+	// conflicts with the version flag are only reported when the flag is not disabled
+	if c.isRoot && !c.settings[DisableVersionFlag] {
+		version := versionFlag()
+		for _, f := range c.flags {
+			if *f != *version && switchesConflict(f.ShortVal, f.LongVal, version.ShortVal, version.LongVal) {
+				record(newDefinitionError(DuplicateDefinition, "duplicate flag: `%v` conflicts with the built-in version flag: `%v` on command: `%v`, disable the version flag using the `DisableVersionFlag` setting to use it", switchesStr(f.ShortVal, f.LongVal), switchesStr(version.ShortVal, version.LongVal), c.name))
+			}
+		}
+		for _, o := range c.options {
+			if switchesConflict(o.ShortVal, o.LongVal, version.ShortVal, version.LongVal) {
+				record(newDefinitionError(DuplicateDefinition, "duplicate option: `%v` conflicts with the built-in version flag: `%v` on command: `%v`, disable the version flag using the `DisableVersionFlag` setting to use it", switchesStr(o.ShortVal, o.LongVal), switchesStr(version.ShortVal, version.LongVal), c.name))
+			}
+		}
+	}
+
+	// global flags and options of ancestors must not collide with the command's own flags and options
+	globals := []struct{ kind, short, long string }{}
+	for p := c.parent; p != nil; p = p.parent {
+		for _, f := range p.flags {
+			if f.IsGlobal {
+				globals = append(globals, struct{ kind, short, long string }{"flag", f.ShortVal, f.LongVal})
+			}
+		}
+		for _, o := range p.options {
+			if o.IsGlobal {
+				globals = append(globals, struct{ kind, short, long string }{"option", o.ShortVal, o.LongVal})
+			}
+		}
+	}
+	for _, g := range globals {
+		for _, f := range c.flags {
+			if switchesConflict(f.ShortVal, f.LongVal, g.short, g.long) {
+				record(newDefinitionError(ConflictingGlobalFlag, "global %v: `%v` conflicts with the flag: `%v` on command: `%v`", g.kind, switchesStr(g.short, g.long), switchesStr(f.ShortVal, f.LongVal), c.name))
+			}
+		}
+		for _, o := range c.options {
+			if switchesConflict(o.ShortVal, o.LongVal, g.short, g.long) {
+				record(newDefinitionError(ConflictingGlobalFlag, "global %v: `%v` conflicts with the option: `%v` on command: `%v`", g.kind, switchesStr(g.short, g.long), switchesStr(o.ShortVal, o.LongVal), c.name))
+			}
+		}
+	}
+
+	for _, g := range c.optionGroups {
+		for _, err := range g.validate() {
+			record(err)
+		}
+	}
+
+	names := []string{}
+	for name := range c.subCmdGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, sc := range c.subCmdGroups[name] {
+			if sc.parent != c {
+				record(newDefinitionError(UnknownReference, "unknown subcommand: `%v` in group: `%v` on command: `%v`", sc.name, name, c.name))
+			}
+		}
+	}
+
+	for _, sc := range c.subCommands {
+		errs = append(errs, sc.Validate()...)
+	}
+
+	return errs
+}
+
+// The subset of `*testing.T` used by `.ValidateForTest()`, so that the package does not depend on the testing package
+type TestReporter interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Validates the command tree from a test and fails the test once for every definition error found, i.e. `app.ValidateForTest(t)`
+func (c *Command) ValidateForTest(t TestReporter) {
+	t.Helper()
+	for _, err := range c.Validate() {
+		t.Errorf("%v: %v", err.Command, err.Message)
+	}
+}
+
+// Collects the definition errors recorded on the command and all of its subcommands while the command tree was built
+func (c *Command) getDefinitionErrors() []error {
+	errs := c.recordedErrors()
+	for _, sc := range c.subCommands {
+		errs = append(errs, sc.getDefinitionErrors()...)
+	}
+	return errs
+}
+
+func (c *Command) recordedErrors() []error {
+	errs := append([]error{}, c.definitionErrs...)
+	for _, a := range c.arguments {
+		errs = append(errs, a.errs...)
+	}
+	for _, o := range c.options {
+		for _, a := range o.arguments() {
+			errs = append(errs, a.errs...)
+		}
+	}
+	return errs
+}
+
+// Checks whether the short or long value of a new flag or option is already used by the command. Conflicts with global flags and options and the built-in version flag are left to the `.Validate()` method since they depend on the final shape of the tree
+func (c *Command) checkConflicts(kind, short, long string) error {
+	skip := func(f *Flag) bool {
+		return c.isRoot && *f == *versionFlag()
+	}
+
+	for _, f := range c.flags {
+		if !skip(f) && (switchesConflict(short, long, f.ShortVal, f.LongVal) || (len(long) > 0 && long == f.negatedLongVal())) {
+			return newDefinitionError(DuplicateDefinition, "duplicate %v: `%v` conflicts with the flag: `%v` on command: `%v`", kind, switchesStr(short, long), switchesStr(f.ShortVal, f.LongVal), c.name)
+		}
+	}
+	for _, o := range c.options {
+		if switchesConflict(short, long, o.ShortVal, o.LongVal) {
+			return newDefinitionError(DuplicateDefinition, "duplicate %v: `%v` conflicts with the option: `%v` on command: `%v`", kind, switchesStr(short, long), switchesStr(o.ShortVal, o.LongVal), c.name)
+		}
+	}
+
+	return nil
+}
+
+func checkSwitchValues(kind, short, long, cmdName string) []DefinitionError {
+	errs := []DefinitionError{}
+	if len(short) > 0 && (len(short) != 2 || short[0] != '-' || short[1] == '-') {
+		errs = append(errs, newDefinitionError(InvalidSwitchValue, "invalid short value: `%v` for %v: `%v` on command: `%v`, short values must be a single dash followed by a single character", short, kind, switchesStr(short, long), cmdName))
+	}
+	if len(long) > 0 && (len(long) < 3 || !strings.HasPrefix(long, "--") || long[2] == '-') {
+		errs = append(errs, newDefinitionError(InvalidSwitchValue, "invalid long value: `%v` for %v: `%v` on command: `%v`, long values must be two dashes followed by a name", long, kind, switchesStr(short, long), cmdName))
+	}
+	return errs
+}
+
+func switchesConflict(short, long, otherShort, otherLong string) bool {
+	return (len(short) > 0 && short == otherShort) || (len(long) > 0 && long == otherLong)
+}
+
+func switchesStr(short, long string) string {
+	return strings.TrimSpace(short + " " + long)
+}
@@ -0,0 +1,83 @@
+package gommander
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	app := App().Name("app")
+	app.Flag("-v --verbose", "Conflicts with the version flag").
+		AddFlag(NewFlag("debug").Short('d').Help("A global flag").Global(true)).
+		Option("-pt --port <int:port>", "A short value with two characters")
+
+	app.SubCommand("deploy").
+		Flag("-d --dry-run", "Conflicts with the global flag").
+		Argument("<fake:target>", "An unknown argument type")
+
+	errs := app.Validate()
+	kinds := map[DefinitionErrorKind]DefinitionError{}
+	for _, e := range errs {
+		kinds[e.Kind] = e
+	}
+
+	assertEq(t, len(errs), 4, "Not all definition errors were reported at once")
+	assert(t, strings.Contains(kinds[DuplicateDefinition].Message, "built-in version flag"), "Conflict with the version flag not reported")
+	assertEq(t, kinds[InvalidSwitchValue].Command, "app", "Invalid short value reported on the wrong command")
+	assertEq(t, kinds[ConflictingGlobalFlag].Command, "app deploy", "Global flag conflict reported on the wrong command")
+	assertEq(t, kinds[InvalidArgumentType].Command, "app deploy", "Unknown argument type reported on the wrong command")
+}
+
+func TestValidateDisabledVersionFlag(t *testing.T) {
+	app := App().Set(DisableVersionFlag, true)
+	app.Flag("-v --verbose", "Verbosity")
+
+	assertEq(t, len(app.Validate()), 0, "Conflict with a disabled version flag reported")
+	assertEq(t, len(app.GetFlags()), 3, "Flag conflicting with a disabled version flag not added")
+
+	err := app.ExecuteFrom([]string{"app", "-v"})
+	assert(t, err == nil, "Flag conflicting with a disabled version flag not parsed")
+}
+
+type _reporter struct{ errs []string }
+
+func (r *_reporter) Helper() {}
+
+func (r *_reporter) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestValidateSubcommandGroups(t *testing.T) {
+	app := App().Name("app")
+	remote := app.SubCommand("remote").Help("Manage tracked repositories")
+	add := remote.SubCommand("add").Help("Add a new remote")
+	app.SubCommandGroup("Collaboration", []*Command{remote, add})
+
+	errs := app.Validate()
+	assertEq(t, len(errs), 1, "Subcommand group member of another command not reported")
+	assertEq(t, errs[0].Kind, UnknownReference, "Subcommand group member reported with the wrong kind")
+	assertEq(t, errs[0].Command, "app", "Subcommand group member reported on the wrong command")
+
+	r := &_reporter{}
+	app.ValidateForTest(r)
+	assertEq(t, len(r.errs), 1, "Definition errors not reported to the test")
+	assertEq(t, r.errs[0], "app: unknown subcommand: `add` in group: `Collaboration` on command: `app`", "Wrong definition error reported to the test")
+
+	valid := App().Name("app")
+	valid.SubCommand("remote").AddToGroup("Collaboration")
+	valid.ValidateForTest(t)
+}
+
+func TestInvalidDefinitionEvent(t *testing.T) {
+	app := App().Set(OverrideAllDefaultListeners, true)
+	app.Option("--port <fake:port>", "An unknown argument type")
+
+	err := app.ExecuteFrom([]string{"app"})
+	e, ok := err.(*Error)
+	assert(t, ok && e.GetKind() == InvalidDefinition, "Invalid definitions not reported through the InvalidDefinition event")
+	assert(t, strings.Contains(e.context, "found unknown argument type"), "Definition errors not listed: ", e.context)
+
+	// parsing within a test binary reports the problems rather than panicking
+	app.ParseFrom([]string{"app"})
+}